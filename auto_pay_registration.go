@@ -0,0 +1,264 @@
+package payletter
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	ErrRegistrationNotFound      = errors.New("auto pay registration not found")
+	ErrRegistrationAlreadyExists = errors.New("auto pay registration already exists")
+	ErrRegistrationConflict      = errors.New("auto pay registration version conflict")
+	ErrInvalidCallback           = errors.New("유효하지 않은 callback")
+)
+
+const defaultRegistrationTTL = 30 * time.Minute
+
+// AutoPayRegistration 자동 결제 등록 요청 한 건, 주문번호로 callback 과 연결
+type AutoPayRegistration struct {
+	OrderNo     string    `json:"order_no"`
+	PgCode      PgCode    `json:"pgcode"`
+	UserID      int64     `json:"user_id"`
+	Amount      int       `json:"amount"` // 0 이면 결제 없는 인증 등록
+	State       string    `json:"state"`
+	TID         string    `json:"tid"`
+	BillKeyID   string    `json:"billkey_id"` // BillKeyVault record id
+	CardCode    string    `json:"card_code"`
+	CardInfo    string    `json:"card_info"`
+	FailReason  string    `json:"fail_reason"`
+	Version     int       `json:"version"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"` // 이 시각 이후의 callback 은 거부
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+type IRegistrationStore interface {
+	// Create 같은 주문번호가 있으면 ErrRegistrationAlreadyExists
+	Create(r *AutoPayRegistration) error
+	// Get 없으면 ErrRegistrationNotFound
+	Get(orderNo string) (AutoPayRegistration, error)
+	// Update 저장된 버전이 r.Version 과 같을 때만 저장하고 r.Version 을 증가, 다르면 ErrRegistrationConflict
+	Update(r *AutoPayRegistration) error
+}
+
+// AutoPayRegistrationFlow 자동 결제 등록 요청부터 callback 의 billkey 저장까지 처리
+type AutoPayRegistrationFlow struct {
+	PayLetter     IPayLetter
+	Store         IRegistrationStore
+	Vault         *BillKeyVault
+	PaymentAPIKey string        // callback payhash 검증용
+	TTL           time.Duration // callback 대기 시간, 0 이면 30분
+	now           Clock
+}
+
+func NewAutoPayRegistrationFlow(p IPayLetter, store IRegistrationStore, vault *BillKeyVault, paymentAPIKey string, opts ...Option) *AutoPayRegistrationFlow {
+	return &AutoPayRegistrationFlow{
+		PayLetter:     p,
+		Store:         store,
+		Vault:         vault,
+		PaymentAPIKey: paymentAPIKey,
+		now:           newOptions(opts).clock,
+	}
+}
+
+// Start 등록 대기 상태를 저장하고 결제창 URL 발급
+func (o *AutoPayRegistrationFlow) Start(req ReqRegisterAutoPay) (res ResRegisterAutoPay, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	ttl := o.TTL
+	if ttl <= 0 {
+		ttl = defaultRegistrationTTL
+	}
+	now := o.now()
+	registration := &AutoPayRegistration{
+		OrderNo:   req.OrderNo,
+		PgCode:    req.PgCode,
+		UserID:    req.UserID,
+		Amount:    req.Amount,
+		State:     RegistrationState.Pending,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+	// callback 이 결제창 URL 응답보다 먼저 올 수 있으므로 먼저 저장
+	if err = o.Store.Create(registration); err != nil {
+		return
+	}
+
+	if res, err = o.PayLetter.RegisterAutoPay(req); err != nil {
+		registration.State = RegistrationState.Failed
+		registration.FailReason = err.Error()
+		_ = o.Store.Update(registration)
+	}
+	return
+}
+
+// StartVerification 결제 없이 카드 인증만 하는 0원 등록
+func (o *AutoPayRegistrationFlow) StartVerification(req ReqRegisterAutoPay) (ResRegisterAutoPay, error) {
+	req.Amount = 0
+	req.AmountBreakdown = AmountBreakdown{}
+	return o.Start(req)
+}
+
+// Complete return, callback POST 처리, 검증 후 billkey 를 vault 에 저장
+//
+// return 과 callback 이 모두 오거나 재전송 되어도 같은 TID 면 처음 저장한 결과를 반환
+func (o *AutoPayRegistrationFlow) Complete(data ResPaymentData) (registration AutoPayRegistration, err error) {
+	if err = data.Validate(o.PaymentAPIKey); err != nil {
+		err = fmt.Errorf("%w: [%s]%v", ErrInvalidCallback, data.OrderNo, err)
+		return
+	}
+
+	if registration, err = o.Store.Get(data.OrderNo); err != nil {
+		return
+	}
+	if done, doneErr := o.completed(registration, data); done {
+		return registration, doneErr
+	}
+	if err = o.check(registration, data); err != nil {
+		return
+	}
+
+	record, err := o.Vault.Put(NewBillKeyMetadata(data), data.BillKey)
+	if err != nil {
+		return
+	}
+
+	registration.State = RegistrationState.Completed
+	registration.TID = data.Tid
+	registration.BillKeyID = record.ID
+	registration.CardCode = data.CardCode
+	registration.CardInfo = data.CardInfo
+	registration.CompletedAt = o.now()
+	if err = o.Store.Update(&registration); err != nil {
+		// 동시에 온 다른 callback 이 먼저 저장, 이번에 저장한 billkey 는 폐기
+		_ = o.Vault.Revoke(record.ID)
+		if errors.Is(err, ErrRegistrationConflict) {
+			if current, getErr := o.Store.Get(data.OrderNo); getErr == nil {
+				if done, doneErr := o.completed(current, data); done {
+					return current, doneErr
+				}
+			}
+		}
+		return
+	}
+	return
+}
+
+// Fail 사용자가 결제창을 닫거나 등록에 실패한 경우
+func (o *AutoPayRegistrationFlow) Fail(orderNo, reason string) (err error) {
+	registration, err := o.Store.Get(orderNo)
+	if err != nil {
+		return
+	}
+	if registration.State != RegistrationState.Pending {
+		return fmt.Errorf("%w: [%s]%s 상태", ErrInvalidCallback, orderNo, registration.State)
+	}
+
+	registration.State = RegistrationState.Failed
+	registration.FailReason = reason
+	return o.Store.Update(&registration)
+}
+
+// BillKey 등록 완료된 주문의 billkey 복호화
+func (o *AutoPayRegistrationFlow) BillKey(orderNo string) (billKey string, err error) {
+	registration, err := o.Store.Get(orderNo)
+	if err != nil {
+		return
+	}
+	if registration.State != RegistrationState.Completed {
+		return "", fmt.Errorf("[%s]등록 완료되지 않음: %s", orderNo, registration.State)
+	}
+	billKey, _, err = o.Vault.Reveal(registration.BillKeyID)
+	return
+}
+
+// completed 이미 처리된 등록이면 done = true, 같은 TID 의 재전송이 아니면 err
+func (o *AutoPayRegistrationFlow) completed(registration AutoPayRegistration, data ResPaymentData) (done bool, err error) {
+	switch registration.State {
+	case RegistrationState.Completed:
+		if registration.TID != data.Tid {
+			err = fmt.Errorf("%w: [%s]다른 TID 로 이미 등록됨 %s", ErrInvalidCallback, data.OrderNo, registration.TID)
+		}
+		return true, err
+	case RegistrationState.Failed:
+		return true, fmt.Errorf("%w: [%s]실패 처리된 등록: %s", ErrInvalidCallback, data.OrderNo, registration.FailReason)
+	default:
+		return false, nil
+	}
+}
+
+func (o *AutoPayRegistrationFlow) check(registration AutoPayRegistration, data ResPaymentData) error {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: [%s]%s", ErrInvalidCallback, data.OrderNo, fmt.Sprintf(format, args...))
+	}
+
+	switch {
+	case o.now().After(registration.ExpiresAt):
+		return invalid("등록 대기 시간 만료 %s", registration.ExpiresAt.Format(time.RFC3339))
+	case parseEventUserID(data.UserID) != registration.UserID:
+		return invalid("사용자 불일치 %s", data.UserID)
+	case data.Amount != registration.Amount:
+		return invalid("금액 불일치 %d != %d", data.Amount, registration.Amount)
+	case data.PgCode != "" && data.PgCode != registration.PgCode:
+		return invalid("결제 수단 불일치 %s", data.PgCode)
+	case data.BillKey == "":
+		return invalid("billkey 없음 [%s]%s", data.Code, data.Message)
+	}
+	return nil
+}
+
+type MemoryRegistrationStore struct {
+	mu            sync.RWMutex
+	registrations map[string]AutoPayRegistration
+}
+
+func NewMemoryRegistrationStore() *MemoryRegistrationStore {
+	return &MemoryRegistrationStore{
+		registrations: make(map[string]AutoPayRegistration),
+	}
+}
+
+func (o *MemoryRegistrationStore) Create(r *AutoPayRegistration) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, exists := o.registrations[r.OrderNo]; exists {
+		return ErrRegistrationAlreadyExists
+	}
+
+	r.Version = 1
+	o.registrations[r.OrderNo] = *r
+	return nil
+}
+
+func (o *MemoryRegistrationStore) Get(orderNo string) (AutoPayRegistration, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	r, exists := o.registrations[orderNo]
+	if !exists {
+		return AutoPayRegistration{}, ErrRegistrationNotFound
+	}
+	return r, nil
+}
+
+func (o *MemoryRegistrationStore) Update(r *AutoPayRegistration) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	stored, exists := o.registrations[r.OrderNo]
+	if !exists {
+		return ErrRegistrationNotFound
+	}
+	if stored.Version != r.Version {
+		return ErrRegistrationConflict
+	}
+
+	r.Version++
+	o.registrations[r.OrderNo] = *r
+	return nil
+}
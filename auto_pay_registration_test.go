@@ -0,0 +1,155 @@
+package payletter
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestRegistrationFlow(t *testing.T, store IRegistrationStore) (*AutoPayRegistrationFlow, *BillKeyVault, *fixedClock) {
+	t.Helper()
+	clock := newFixedClock(time.Date(2024, 3, 1, 12, 0, 0, 0, kst))
+	vault, _, _ := newTestVault(t)
+	flow := NewAutoPayRegistrationFlow(&fakePayLetter{}, store, vault, testPaymentAPIKey, WithClock(clock.Now))
+	return flow, vault, clock
+}
+
+// addPendingRegistration Start 로 결제창 URL 을 발급한 상태
+func addPendingRegistration(t *testing.T, store IRegistrationStore, at time.Time) {
+	t.Helper()
+	err := store.Create(&AutoPayRegistration{
+		OrderNo:   "order-1",
+		PgCode:    PgCodeCreditCard,
+		UserID:    100,
+		State:     RegistrationState.Pending,
+		CreatedAt: at,
+		ExpiresAt: at.Add(defaultRegistrationTTL),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func signedRegistrationCallback(tid string) ResPaymentData {
+	data := ResPaymentData{UserID: "100", OrderNo: "order-1", Tid: tid, PgCode: PgCodeCreditCard, BillKey: "BILLKEY-" + tid, CardInfo: "1234-****"}
+	data.PayHash = NewSigner("", testPaymentAPIKey).Sign(HashRecipePaymentCallback, HashParams{UserID: data.UserID, Amount: data.Amount, TID: data.Tid})
+	return data
+}
+
+func TestAutoPayRegistrationDuplicateCallback(t *testing.T) {
+	store := NewMemoryRegistrationStore()
+	flow, vault, clock := newTestRegistrationFlow(t, store)
+	addPendingRegistration(t, store, clock.Now())
+
+	first, err := flow.Complete(signedRegistrationCallback("tid-1"))
+	if err != nil || first.State != RegistrationState.Completed {
+		t.Fatalf("registration %+v, err %v", first, err)
+	}
+
+	// return 과 callback 이 모두 옴
+	second, err := flow.Complete(signedRegistrationCallback("tid-1"))
+	if err != nil || second.BillKeyID != first.BillKeyID {
+		t.Fatalf("재전송 registration %+v, err %v", second, err)
+	}
+	if records, _ := vault.ListByUser(100); len(records) != 1 {
+		t.Fatalf("billkey %d 개 저장", len(records))
+	}
+	if billKey, err := flow.BillKey("order-1"); err != nil || billKey != "BILLKEY-tid-1" {
+		t.Fatalf("billkey %q, err %v", billKey, err)
+	}
+
+	// 같은 주문번호에 다른 TID
+	if _, err = flow.Complete(signedRegistrationCallback("tid-2")); !errors.Is(err, ErrInvalidCallback) {
+		t.Fatalf("다른 TID err = %v, want ErrInvalidCallback", err)
+	}
+	if billKey, _ := flow.BillKey("order-1"); billKey != "BILLKEY-tid-1" {
+		t.Fatalf("다른 TID 로 billkey 가 바뀜 %q", billKey)
+	}
+}
+
+func TestAutoPayRegistrationRejectsInvalidCallback(t *testing.T) {
+	store := NewMemoryRegistrationStore()
+	flow, vault, clock := newTestRegistrationFlow(t, store)
+	addPendingRegistration(t, store, clock.Now())
+
+	other := signedRegistrationCallback("tid-1")
+	other.UserID = "200"
+	other.PayHash = NewSigner("", testPaymentAPIKey).Sign(HashRecipePaymentCallback, HashParams{UserID: other.UserID, Amount: other.Amount, TID: other.Tid})
+	if _, err := flow.Complete(other); !errors.Is(err, ErrInvalidCallback) {
+		t.Fatalf("다른 사용자 err = %v", err)
+	}
+
+	clock.Advance(defaultRegistrationTTL + time.Second)
+	if _, err := flow.Complete(signedRegistrationCallback("tid-1")); !errors.Is(err, ErrInvalidCallback) {
+		t.Fatalf("만료 후 err = %v", err)
+	}
+
+	if records, _ := vault.ListByUser(100); len(records) != 0 {
+		t.Fatalf("거부한 callback 의 billkey %d 개 저장", len(records))
+	}
+	if registration, _ := store.Get("order-1"); registration.State != RegistrationState.Pending {
+		t.Fatalf("state %s", registration.State)
+	}
+}
+
+// racingRegistrationStore 첫 Update 직전에 다른 callback 을 처리
+type racingRegistrationStore struct {
+	*MemoryRegistrationStore
+	beforeUpdate func()
+}
+
+func (o *racingRegistrationStore) Update(r *AutoPayRegistration) error {
+	if f := o.beforeUpdate; f != nil {
+		o.beforeUpdate = nil
+		f()
+	}
+	return o.MemoryRegistrationStore.Update(r)
+}
+
+func TestAutoPayRegistrationConcurrentComplete(t *testing.T) {
+	tests := []struct {
+		name    string
+		tid     string
+		wantErr error
+	}{
+		{"같은 TID", "tid-1", nil},
+		{"다른 TID", "tid-2", ErrInvalidCallback},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &racingRegistrationStore{MemoryRegistrationStore: NewMemoryRegistrationStore()}
+			flow, vault, clock := newTestRegistrationFlow(t, store)
+			addPendingRegistration(t, store, clock.Now())
+
+			var winner AutoPayRegistration
+			store.beforeUpdate = func() {
+				var err error
+				if winner, err = flow.Complete(signedRegistrationCallback("tid-1")); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			registration, err := flow.Complete(signedRegistrationCallback(tt.tid))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && registration.BillKeyID != winner.BillKeyID {
+				t.Fatalf("billkey id %s, want %s", registration.BillKeyID, winner.BillKeyID)
+			}
+
+			// 버전 경쟁에서 진 callback 이 저장한 billkey 는 폐기
+			records, _ := vault.ListByUser(100)
+			if len(records) != 2 {
+				t.Fatalf("billkey %d 개 저장", len(records))
+			}
+			for _, record := range records {
+				if record.IsRevoked() == (record.ID == winner.BillKeyID) {
+					t.Fatalf("record %s revoked %v, 등록된 billkey %s", record.ID, record.IsRevoked(), winner.BillKeyID)
+				}
+			}
+			if billKey, _ := flow.BillKey("order-1"); billKey != "BILLKEY-tid-1" {
+				t.Fatalf("billkey %q", billKey)
+			}
+		})
+	}
+}
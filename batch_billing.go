@@ -0,0 +1,504 @@
+package payletter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrBatchItemInProgress 다른 실행이 같은 배치의 주문을 처리 중
+var ErrBatchItemInProgress = errors.New("다른 실행이 처리 중인 배치 주문")
+
+const (
+	defaultBatchWorkers      = 4
+	defaultBatchClaimTimeout = 10 * time.Minute
+)
+
+// BatchCheckpoint 배치 결제 한 건의 진행 상태, 주문번호 단위
+type BatchCheckpoint struct {
+	BatchID    string    `json:"batch_id"`
+	OrderNo    string    `json:"order_no"`
+	Status     string    `json:"status"`
+	TID        string    `json:"tid"`
+	Amount     int       `json:"amount"`
+	Message    string    `json:"message"`  // 거절, 에러 메시지
+	Attempts   int       `json:"attempts"` // 처리 시작 횟수, 1 보다 크면 이전 실행이 중단된 주문
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+type IBatchCheckpointStore interface {
+	// Begin 주문 처리 시작 기록, 동시에 같은 주문을 Begin 하면 하나만 proceed
+	//  - 처음이면 InProgress 로 저장하고 proceed = true
+	//  - Succeeded, Declined 이면 저장된 checkpoint 와 proceed = false
+	//  - 시작한 지 claim timeout 이 지나지 않은 InProgress 이면 ErrBatchItemInProgress
+	//  - 그 외 InProgress, Failed, Unknown 이면 Attempts 를 증가하고 이전 checkpoint 와 proceed = true
+	Begin(batchID, orderNo string, at time.Time) (previous BatchCheckpoint, proceed bool, err error)
+	// Finish 처리 결과 저장
+	Finish(checkpoint BatchCheckpoint) error
+}
+
+type BatchItemResult struct {
+	OrderNo string `json:"order_no"`
+	UserID  int64  `json:"user_id"`
+	Amount  int    `json:"amount"`
+	Status  string `json:"status"`
+	TID     string `json:"tid"`
+	Skipped bool   `json:"skipped"` // 이전 실행에서 이미 처리된 주문
+	Err     error  `json:"-"`
+}
+
+type BatchReport struct {
+	BatchID         string            `json:"batch_id"`
+	Total           int               `json:"total"`
+	Succeeded       int               `json:"succeeded"`
+	Declined        int               `json:"declined"`
+	Failed          int               `json:"failed"`
+	Unknown         int               `json:"unknown"`          // 결제 여부를 알 수 없는 주문, 같은 batchID 로 재개하면 조회 후 처리
+	Skipped         int               `json:"skipped"`          // 이전 실행에서 처리되어 건너뛴 주문, 상태별 수에도 포함
+	SucceededAmount KRW               `json:"succeeded_amount"` // 이번 실행과 이전 실행에서 결제된 금액
+	Declines        []BatchItemResult `json:"declines"`
+	Errors          []BatchItemResult `json:"errors"`
+	StartedAt       time.Time         `json:"started_at"`
+	FinishedAt      time.Time         `json:"finished_at"`
+}
+
+func (o *BatchReport) add(result BatchItemResult) {
+	o.Total++
+	if result.Skipped {
+		o.Skipped++
+	}
+	switch result.Status {
+	case BatchItemStatus.Succeeded:
+		o.Succeeded++
+		o.SucceededAmount += KRW(result.Amount)
+	case BatchItemStatus.Declined:
+		o.Declined++
+		o.Declines = append(o.Declines, result)
+	case BatchItemStatus.Unknown:
+		o.Unknown++
+		o.Errors = append(o.Errors, result)
+	default:
+		o.Failed++
+		o.Errors = append(o.Errors, result)
+	}
+}
+
+// BatchBillingRunner billkey 자동 결제를 worker pool 로 실행
+//
+// 같은 batchID 로 다시 실행하면 결제된 주문은 건너뛰고, 중단되었거나 결과를 알 수 없는 주문은 GetTransaction 으로
+// 결제되지 않은 것을 확인한 뒤에만 다시 결제. 결과 조회는 결제 시작일과 다음 날 거래 목록에서 찾음.
+// 처리 중(InProgress)인 주문은 다른 실행이 결제 중일 수 있으므로 checkpoint store 의 ClaimTimeout 이 지난 뒤에 다시 처리
+type BatchBillingRunner struct {
+	PayLetter  IPayLetter
+	Checkpoint IBatchCheckpointStore
+	Workers    int // 0 이면 4
+	RateLimit  int // 초당 최대 결제 요청 수, 0 이거나 1e9 보다 크면 제한 없음
+	// DeclineCodes 결제 거절로 처리할 페이레터 에러 코드 (한도 초과, 정지 카드 등), 재개 시 다시 결제하지 않음
+	DeclineCodes []string
+	// IsDecline 결제 거절인지 여부, nil 이면 DeclineCodes 에 있는 에러 코드
+	IsDecline func(err error) bool
+	OnResult  func(result BatchItemResult) // 주문 처리 완료 시 호출 (로그, 진행률 용), 동시에 호출되지 않음
+	now       Clock
+}
+
+func NewBatchBillingRunner(p IPayLetter, checkpoint IBatchCheckpointStore, opts ...Option) *BatchBillingRunner {
+	return &BatchBillingRunner{
+		PayLetter:  p,
+		Checkpoint: checkpoint,
+		now:        newOptions(opts).clock,
+	}
+}
+
+// Run reqs 가 닫히거나 ctx 가 취소될 때까지 결제, 취소되면 처리 중인 결제는 끝까지 기다린 후 ctx.Err() 반환
+func (o *BatchBillingRunner) Run(ctx context.Context, batchID string, reqs <-chan ReqTransactionAutoPay) (report BatchReport, err error) {
+	report = BatchReport{
+		BatchID:   batchID,
+		StartedAt: o.now(),
+	}
+
+	workers := o.Workers
+	if workers <= 0 {
+		workers = defaultBatchWorkers
+	}
+
+	jobs := make(chan ReqTransactionAutoPay)
+	results := make(chan BatchItemResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for req := range jobs {
+				results <- o.safeProcess(batchID, req)
+			}
+		}()
+	}
+
+	go func() {
+		o.dispatch(ctx, reqs, jobs, results)
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	for result := range results {
+		report.add(result)
+		if o.OnResult != nil {
+			o.OnResult(result)
+		}
+	}
+
+	report.FinishedAt = o.now()
+	err = ctx.Err()
+	return
+}
+
+func (o *BatchBillingRunner) dispatch(ctx context.Context, reqs <-chan ReqTransactionAutoPay, jobs chan<- ReqTransactionAutoPay, results chan<- BatchItemResult) {
+	var tick <-chan time.Time
+	if o.RateLimit > 0 && time.Second/time.Duration(o.RateLimit) > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(o.RateLimit))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	seen := make(map[string]bool)
+	for {
+		var req ReqTransactionAutoPay
+		select {
+		case <-ctx.Done():
+			return
+		case r, ok := <-reqs:
+			if !ok {
+				return
+			}
+			req = r
+		}
+
+		// 주문번호는 checkpoint 와 재개 시 결제 여부 조회의 key
+		if req.OrderNo == "" {
+			results <- batchItemResult(req, BatchItemStatus.Failed, validate(rules(required("order_no", req.OrderNo))))
+			continue
+		}
+
+		// 같은 주문번호를 동시에 처리하면 중단 여부를 구분할 수 없어 중복 결제될 수 있음
+		if seen[req.OrderNo] {
+			results <- batchItemResult(req, BatchItemStatus.Failed, fmt.Errorf("[%s]배치에 중복된 주문번호", req.OrderNo))
+			continue
+		}
+		seen[req.OrderNo] = true
+
+		if tick != nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick:
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case jobs <- req:
+		}
+	}
+}
+
+// safeProcess process 중 panic 이 나면 결제 여부를 알 수 없으므로 Unknown, checkpoint 는 InProgress 로 남아 재개 시 조회
+func (o *BatchBillingRunner) safeProcess(batchID string, req ReqTransactionAutoPay) (result BatchItemResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = batchItemResult(req, BatchItemStatus.Unknown, fmt.Errorf("[%s]%w: %v", req.OrderNo, errPanicked, r))
+		}
+	}()
+	return o.process(batchID, req)
+}
+
+func (o *BatchBillingRunner) process(batchID string, req ReqTransactionAutoPay) (result BatchItemResult) {
+	startedAt := o.now()
+	previous, proceed, err := o.Checkpoint.Begin(batchID, req.OrderNo, startedAt)
+	if errors.Is(err, ErrBatchItemInProgress) {
+		// 다른 실행의 결과를 알 수 없음, checkpoint 는 그대로 두고 나중에 재개하면 조회 후 처리
+		return batchItemResult(req, BatchItemStatus.Unknown, fmt.Errorf("[%s]%w", req.OrderNo, err))
+	}
+	if err != nil {
+		return batchItemResult(req, BatchItemStatus.Failed, err)
+	}
+	if !proceed {
+		result = batchItemResult(req, previous.Status, nil)
+		result.TID = previous.TID
+		result.Skipped = true
+		if previous.Message != "" {
+			result.Err = errors.New(previous.Message)
+		}
+		return
+	}
+
+	checkpoint := BatchCheckpoint{
+		BatchID:   batchID,
+		OrderNo:   req.OrderNo,
+		Attempts:  previous.Attempts + 1,
+		StartedAt: startedAt,
+	}
+
+	charged := false
+	if previous.Status == BatchItemStatus.InProgress || previous.Status == BatchItemStatus.Unknown {
+		// 이전 실행이 결제 요청 후 중단되었을 수 있으므로 결제 여부 확인
+		if charged, result = o.reconcile(req, previous); result.Err != nil {
+			return o.finish(checkpoint, result)
+		}
+	}
+
+	if !charged {
+		res, chargeErr := o.PayLetter.TransactionAutoPay(req)
+		result = batchItemResult(req, o.status(chargeErr), chargeErr)
+		result.TID = res.TID
+	}
+
+	return o.finish(checkpoint, result)
+}
+
+// status 결제 요청 결과, 결제되지 않은 것이 확실하지 않으면 Unknown
+func (o *BatchBillingRunner) status(err error) string {
+	var e *PayLetterError
+	switch {
+	case err == nil:
+		return BatchItemStatus.Succeeded
+	case o.isDecline(err):
+		return BatchItemStatus.Declined
+	case isLocalError(err), errors.Is(err, ErrPayLetterUnavailable):
+		return BatchItemStatus.Failed // 페이레터를 호출하지 않음
+	case errors.As(err, &e) && !e.Server:
+		return BatchItemStatus.Failed
+	default:
+		return BatchItemStatus.Unknown
+	}
+}
+
+// reconcile 이전 시도로 결제되었는지 조회
+//
+// 결제 시작일과 다음 날(자정 직전 결제) 모두에서 ErrTransactionNotFound 일 때만 charged = false,
+// 조회에 실패하면 다시 결제하지 않고 Unknown 과 result.Err
+func (o *BatchBillingRunner) reconcile(req ReqTransactionAutoPay, previous BatchCheckpoint) (charged bool, result BatchItemResult) {
+	startedAt := previous.StartedAt
+	if startedAt.IsZero() {
+		startedAt = o.now()
+	}
+	started := startedAt.In(kst)
+	today := o.now().In(kst).Format(transactionDateLayout)
+
+	for _, day := range []time.Time{started, started.AddDate(0, 0, 1)} {
+		date := day.Format(transactionDateLayout)
+		if date > today {
+			break
+		}
+
+		transaction, err := o.PayLetter.GetTransaction(ReqGetTransaction{
+			PgCode:  req.PgCode,
+			OrderNo: req.OrderNo,
+			Date:    date,
+		})
+		if errors.Is(err, ErrTransactionNotFound) {
+			continue
+		}
+		if err != nil {
+			return false, batchItemResult(req, BatchItemStatus.Unknown, fmt.Errorf("[%s]이전 결제 여부 확인 실패: %w", req.OrderNo, err))
+		}
+
+		result = batchItemResult(req, BatchItemStatus.Succeeded, nil)
+		result.TID = transaction.TID
+		return true, result
+	}
+	return false, result
+}
+
+func (o *BatchBillingRunner) finish(checkpoint BatchCheckpoint, result BatchItemResult) BatchItemResult {
+	checkpoint.Status = result.Status
+	checkpoint.TID = result.TID
+	checkpoint.Amount = result.Amount
+	checkpoint.FinishedAt = o.now()
+	if result.Err != nil {
+		checkpoint.Message = result.Err.Error()
+	}
+
+	if err := o.Checkpoint.Finish(checkpoint); err != nil && result.Err == nil {
+		// 결제는 되었지만 기록하지 못함, 재개 시 조회로 확인
+		result.Err = fmt.Errorf("[%s]checkpoint 저장 실패: %w", checkpoint.OrderNo, err)
+	}
+	return result
+}
+
+func (o *BatchBillingRunner) isDecline(err error) bool {
+	if o.IsDecline != nil {
+		return o.IsDecline(err)
+	}
+
+	var e *PayLetterError
+	if !errors.As(err, &e) || e.Server {
+		return false
+	}
+	for _, code := range o.DeclineCodes {
+		if e.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+func batchItemResult(req ReqTransactionAutoPay, status string, err error) BatchItemResult {
+	return BatchItemResult{
+		OrderNo: req.OrderNo,
+		UserID:  req.UserID,
+		Amount:  req.Amount,
+		Status:  status,
+		Err:     err,
+	}
+}
+
+type MemoryBatchCheckpointStore struct {
+	mu           sync.Mutex
+	checkpoints  map[string]BatchCheckpoint
+	ClaimTimeout time.Duration // InProgress 주문을 다른 실행이 처리 중으로 보는 시간, 0 이면 10분
+}
+
+func NewMemoryBatchCheckpointStore() *MemoryBatchCheckpointStore {
+	return &MemoryBatchCheckpointStore{
+		checkpoints: make(map[string]BatchCheckpoint),
+	}
+}
+
+func (o *MemoryBatchCheckpointStore) Begin(batchID, orderNo string, at time.Time) (previous BatchCheckpoint, proceed bool, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	key := batchID + "/" + orderNo
+	previous, exists := o.checkpoints[key]
+	if exists && (previous.Status == BatchItemStatus.Succeeded || previous.Status == BatchItemStatus.Declined) {
+		return previous, false, nil
+	}
+	if exists && claimed(previous, at, o.ClaimTimeout) {
+		return previous, false, ErrBatchItemInProgress
+	}
+
+	o.checkpoints[key] = BatchCheckpoint{
+		BatchID:   batchID,
+		OrderNo:   orderNo,
+		Status:    BatchItemStatus.InProgress,
+		Attempts:  previous.Attempts + 1,
+		StartedAt: at,
+	}
+	return previous, true, nil
+}
+
+func (o *MemoryBatchCheckpointStore) Finish(checkpoint BatchCheckpoint) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.checkpoints[checkpoint.BatchID+"/"+checkpoint.OrderNo] = checkpoint
+	return nil
+}
+
+// BatchCheckpointTableDDL SQLBatchCheckpointStore 용 테이블 (MySQL 기준)
+const BatchCheckpointTableDDL = `CREATE TABLE payletter_batch_checkpoint (
+	batch_id    VARCHAR(100) NOT NULL,
+	order_no    VARCHAR(50)  NOT NULL,
+	status      VARCHAR(20)  NOT NULL,
+	tid         VARCHAR(100) NOT NULL,
+	amount      INT          NOT NULL,
+	message     TEXT         NOT NULL,
+	attempts    INT          NOT NULL,
+	started_at  BIGINT       NOT NULL,
+	finished_at BIGINT       NOT NULL,
+	PRIMARY KEY (batch_id, order_no)
+)`
+
+// SQLBatchCheckpointStore database/sql 기반 저장소, placeholder 는 ? 를 사용 (MySQL, SQLite)
+type SQLBatchCheckpointStore struct {
+	DB           *sql.DB
+	Table        string        // 비어 있으면 payletter_batch_checkpoint
+	ClaimTimeout time.Duration // InProgress 주문을 다른 실행이 처리 중으로 보는 시간, 0 이면 10분
+}
+
+func NewSQLBatchCheckpointStore(db *sql.DB) *SQLBatchCheckpointStore {
+	return &SQLBatchCheckpointStore{
+		DB: db,
+	}
+}
+
+func (o *SQLBatchCheckpointStore) table() string {
+	if o.Table == "" {
+		return "payletter_batch_checkpoint"
+	}
+	return o.Table
+}
+
+func (o *SQLBatchCheckpointStore) Begin(batchID, orderNo string, at time.Time) (previous BatchCheckpoint, proceed bool, err error) {
+	var startedAt, finishedAt int64
+	err = o.DB.QueryRow(
+		fmt.Sprintf("SELECT batch_id, order_no, status, tid, amount, message, attempts, started_at, finished_at FROM %s WHERE batch_id = ? AND order_no = ?", o.table()),
+		batchID, orderNo,
+	).Scan(&previous.BatchID, &previous.OrderNo, &previous.Status, &previous.TID, &previous.Amount, &previous.Message, &previous.Attempts, &startedAt, &finishedAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		_, err = o.DB.Exec(
+			fmt.Sprintf("INSERT INTO %s (batch_id, order_no, status, tid, amount, message, attempts, started_at, finished_at) VALUES (?, ?, ?, '', 0, '', 1, ?, 0)", o.table()),
+			batchID, orderNo, BatchItemStatus.InProgress, at.UnixMilli(),
+		)
+		if err != nil {
+			// 중복 key 에러 판별은 driver 마다 다르므로 동시에 시작한 다른 실행이 먼저 저장한 것으로 봄
+			return BatchCheckpoint{}, false, fmt.Errorf("%w: %v", ErrBatchItemInProgress, err)
+		}
+		return BatchCheckpoint{}, true, nil
+	}
+	if err != nil {
+		return
+	}
+
+	previous.StartedAt = time.UnixMilli(startedAt)
+	if finishedAt > 0 {
+		previous.FinishedAt = time.UnixMilli(finishedAt)
+	}
+	if previous.Status == BatchItemStatus.Succeeded || previous.Status == BatchItemStatus.Declined {
+		return previous, false, nil
+	}
+	if claimed(previous, at, o.ClaimTimeout) {
+		return previous, false, ErrBatchItemInProgress
+	}
+
+	// 조회한 상태 그대로일 때만 선점, 동시에 재개한 다른 실행과 경쟁
+	result, err := o.DB.Exec(
+		fmt.Sprintf("UPDATE %s SET status = ?, attempts = attempts + 1, started_at = ? WHERE batch_id = ? AND order_no = ? AND status = ? AND attempts = ?", o.table()),
+		BatchItemStatus.InProgress, at.UnixMilli(), batchID, orderNo, previous.Status, previous.Attempts,
+	)
+	if err != nil {
+		return
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affected == 0 {
+		return previous, false, ErrBatchItemInProgress
+	}
+	return previous, true, nil
+}
+
+// claimed 다른 실행이 시작한 지 claimTimeout 이 지나지 않은 InProgress 주문
+func claimed(previous BatchCheckpoint, at time.Time, claimTimeout time.Duration) bool {
+	if claimTimeout <= 0 {
+		claimTimeout = defaultBatchClaimTimeout
+	}
+	return previous.Status == BatchItemStatus.InProgress && at.Before(previous.StartedAt.Add(claimTimeout))
+}
+
+func (o *SQLBatchCheckpointStore) Finish(checkpoint BatchCheckpoint) (err error) {
+	_, err = o.DB.Exec(
+		fmt.Sprintf("UPDATE %s SET status = ?, tid = ?, amount = ?, message = ?, finished_at = ? WHERE batch_id = ? AND order_no = ?", o.table()),
+		checkpoint.Status, checkpoint.TID, checkpoint.Amount, checkpoint.Message, checkpoint.FinishedAt.UnixMilli(), checkpoint.BatchID, checkpoint.OrderNo,
+	)
+	return
+}
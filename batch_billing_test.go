@@ -0,0 +1,191 @@
+package payletter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func runBatch(t *testing.T, runner *BatchBillingRunner, batchID string, orderNos ...string) BatchReport {
+	t.Helper()
+	reqs := make(chan ReqTransactionAutoPay, len(orderNos))
+	for _, orderNo := range orderNos {
+		reqs <- ReqTransactionAutoPay{PgCode: PgCodeCreditCard, UserID: 1, OrderNo: orderNo, Amount: 1000}
+	}
+	close(reqs)
+
+	report, err := runner.Run(context.Background(), batchID, reqs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return report
+}
+
+func newTestBatchRunner(p IPayLetter) *BatchBillingRunner {
+	clock := newFixedClock(time.Date(2024, 3, 1, 12, 0, 0, 0, kst))
+	runner := NewBatchBillingRunner(p, NewMemoryBatchCheckpointStore(), WithClock(clock.Now))
+	runner.DeclineCodes = []string{"1001"}
+	return runner
+}
+
+func TestBatchBillingDeclineIsFinal(t *testing.T) {
+	p := &fakePayLetter{
+		transactionAutoPay: func(req ReqTransactionAutoPay) (ResTransactionAutoPay, error) {
+			return ResTransactionAutoPay{}, &PayLetterError{Code: "1001", Message: "한도 초과"}
+		},
+	}
+	runner := newTestBatchRunner(p)
+
+	report := runBatch(t, runner, "batch", "order-1")
+	if report.Declined != 1 {
+		t.Fatalf("report %+v", report)
+	}
+
+	report = runBatch(t, runner, "batch", "order-1")
+	if report.Declined != 1 || report.Skipped != 1 || p.count("TransactionAutoPay") != 1 {
+		t.Fatalf("거절된 주문을 다시 결제함: report %+v, calls %d", report, p.count("TransactionAutoPay"))
+	}
+}
+
+func TestBatchBillingOtherErrorsAreNotDeclines(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{&PayLetterError{Code: "2002", Message: "잘못된 billkey"}, BatchItemStatus.Failed},
+		{&PayLetterError{Code: "500", Message: "internal error", Server: true}, BatchItemStatus.Unknown},
+		{errors.New("connection reset"), BatchItemStatus.Unknown},
+		{&ValidationError{}, BatchItemStatus.Failed},
+	}
+	for _, tt := range tests {
+		p := &fakePayLetter{
+			transactionAutoPay: func(req ReqTransactionAutoPay) (ResTransactionAutoPay, error) {
+				return ResTransactionAutoPay{}, tt.err
+			},
+		}
+		var results []BatchItemResult
+		runner := newTestBatchRunner(p)
+		runner.OnResult = func(result BatchItemResult) { results = append(results, result) }
+
+		runBatch(t, runner, "batch", "order-1")
+		if len(results) != 1 || results[0].Status != tt.want {
+			t.Errorf("%v: results %+v, want %s", tt.err, results, tt.want)
+		}
+	}
+}
+
+func TestBatchBillingResumeUnknown(t *testing.T) {
+	charge := func(req ReqTransactionAutoPay) (ResTransactionAutoPay, error) {
+		return ResTransactionAutoPay{}, errors.New("timeout")
+	}
+	p := &fakePayLetter{
+		transactionAutoPay: func(req ReqTransactionAutoPay) (ResTransactionAutoPay, error) {
+			return charge(req)
+		},
+	}
+	runner := newTestBatchRunner(p)
+
+	report := runBatch(t, runner, "batch", "order-1", "order-2", "order-3")
+	if report.Unknown != 3 {
+		t.Fatalf("report %+v", report)
+	}
+
+	// order-1 은 결제됨, order-2 는 결제되지 않음, order-3 은 조회 실패
+	p.getTransaction = func(req ReqGetTransaction) (ResGetTransaction, error) {
+		switch req.OrderNo {
+		case "order-1":
+			return ResGetTransaction{Transaction: Transaction{TID: "tid-1", OrderNo: req.OrderNo}}, nil
+		case "order-2":
+			return ResGetTransaction{}, ErrTransactionNotFound
+		default:
+			return ResGetTransaction{}, &PayLetterError{Code: "500", Server: true}
+		}
+	}
+	var charged []string
+	charge = func(req ReqTransactionAutoPay) (ResTransactionAutoPay, error) {
+		charged = append(charged, req.OrderNo)
+		return ResTransactionAutoPay{TID: "tid-new"}, nil
+	}
+	runner.Workers = 1
+
+	report = runBatch(t, runner, "batch", "order-1", "order-2", "order-3")
+	if len(charged) != 1 || charged[0] != "order-2" {
+		t.Fatalf("다시 결제한 주문 %v, want [order-2]", charged)
+	}
+	if report.Succeeded != 2 || report.Unknown != 1 {
+		t.Fatalf("report %+v", report)
+	}
+}
+
+func TestBatchBillingRecoversPanic(t *testing.T) {
+	p := &fakePayLetter{
+		transactionAutoPay: func(req ReqTransactionAutoPay) (ResTransactionAutoPay, error) {
+			if req.OrderNo == "order-1" {
+				panic("unexpected response")
+			}
+			return ResTransactionAutoPay{TID: "tid"}, nil
+		},
+	}
+
+	report := runBatch(t, newTestBatchRunner(p), "batch", "order-1", "order-2")
+	if report.Unknown != 1 || report.Succeeded != 1 {
+		t.Fatalf("report %+v", report)
+	}
+	if !errors.Is(report.Errors[0].Err, errPanicked) {
+		t.Fatalf("err = %v", report.Errors[0].Err)
+	}
+}
+
+func TestBatchCheckpointClaim(t *testing.T) {
+	store := NewMemoryBatchCheckpointStore()
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, kst)
+
+	if _, proceed, err := store.Begin("batch", "order-1", at); err != nil || !proceed {
+		t.Fatalf("첫 시작: proceed %v, err %v", proceed, err)
+	}
+
+	// 중단된 실행인지 처리 중인 실행인지 알 수 없으므로 claim timeout 전에는 선점하지 않음
+	if _, proceed, err := store.Begin("batch", "order-1", at.Add(time.Minute)); !errors.Is(err, ErrBatchItemInProgress) || proceed {
+		t.Fatalf("처리 중: proceed %v, err %v", proceed, err)
+	}
+
+	previous, proceed, err := store.Begin("batch", "order-1", at.Add(defaultBatchClaimTimeout))
+	if err != nil || !proceed || previous.Status != BatchItemStatus.InProgress {
+		t.Fatalf("claim timeout 이후: previous %+v, proceed %v, err %v", previous, proceed, err)
+	}
+}
+
+func TestBatchBillingSkipsItemClaimedByAnotherRun(t *testing.T) {
+	p := &fakePayLetter{
+		transactionAutoPay: func(req ReqTransactionAutoPay) (ResTransactionAutoPay, error) {
+			return ResTransactionAutoPay{TID: "tid"}, nil
+		},
+	}
+	runner := newTestBatchRunner(p)
+	if _, _, err := runner.Checkpoint.Begin("batch", "order-1", runner.now()); err != nil {
+		t.Fatal(err)
+	}
+
+	report := runBatch(t, runner, "batch", "order-1")
+	if report.Unknown != 1 || !errors.Is(report.Errors[0].Err, ErrBatchItemInProgress) {
+		t.Fatalf("report %+v", report)
+	}
+	if p.count("TransactionAutoPay") != 0 || p.count("GetTransaction") != 0 {
+		t.Fatal("다른 실행이 처리 중인 주문을 결제함")
+	}
+}
+
+func TestBatchBillingRejectsEmptyOrderNo(t *testing.T) {
+	p := &fakePayLetter{}
+	runner := newTestBatchRunner(p)
+	runner.RateLimit = 2e9 // 간격이 0 이 되는 값은 제한 없음
+
+	report := runBatch(t, runner, "batch", "")
+	if report.Failed != 1 || !errors.Is(report.Errors[0].Err, ErrValidation) {
+		t.Fatalf("report %+v", report)
+	}
+	if p.count("TransactionAutoPay") != 0 {
+		t.Fatal("주문번호 없이 결제함")
+	}
+}
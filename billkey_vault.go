@@ -0,0 +1,593 @@
+package payletter
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrBillKeyNotFound      = errors.New("billkey not found")
+	ErrBillKeyAlreadyExists = errors.New("billkey already exists")
+	ErrBillKeyRevoked       = errors.New("billkey revoked")
+	ErrUnknownKey           = errors.New("알 수 없는 암호화 key")
+)
+
+const dataKeySize = 32 // AES-256
+
+// DataKey billkey 하나를 암호화하는 key, 평문 key 는 저장하지 않고 master key 로 암호화한 key 만 저장
+type DataKey struct {
+	KeyID      string // data key 를 암호화한 master key
+	Plaintext  []byte
+	Ciphertext []byte
+}
+
+// IKeyProvider KMS 처럼 master key 로 data key 를 발급, 복호화
+type IKeyProvider interface {
+	// CurrentKeyID 새 data key 발급에 사용하는 master key, 이 key 가 아닌 billkey 는 Rotate 대상
+	CurrentKeyID() string
+	GenerateDataKey() (DataKey, error)
+	DecryptDataKey(keyID string, ciphertext []byte) ([]byte, error)
+}
+
+// BillKeyMetadata 암호화하지 않고 저장하는 billkey 정보
+type BillKeyMetadata struct {
+	UserID    int64  `json:"user_id"`
+	PgCode    PgCode `json:"pgcode"`
+	CardCode  string `json:"card_code"`
+	CardInfo  string `json:"card_info"` // 마스킹 된 카드 번호
+	AliasName string `json:"alias_name"`
+}
+
+// NewBillKeyMetadata 결제 callback 의 사용자, 카드 정보
+func NewBillKeyMetadata(data ResPaymentData) BillKeyMetadata {
+	return BillKeyMetadata{
+		UserID:   parseEventUserID(data.UserID),
+		PgCode:   data.PgCode,
+		CardCode: data.CardCode,
+		CardInfo: data.CardInfo,
+	}
+}
+
+func (o BillKeyMetadata) CardName() string {
+	return CardCode.ValueMap[o.CardCode]
+}
+
+type BillKeyRecord struct {
+	ID string `json:"id"`
+	BillKeyMetadata
+	KeyID            string    `json:"key_id"`
+	EncryptedDataKey []byte    `json:"encrypted_data_key"`
+	Nonce            []byte    `json:"nonce"`
+	Ciphertext       []byte    `json:"ciphertext"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	RevokedAt        time.Time `json:"revoked_at"` // 폐기하지 않았으면 zero time
+}
+
+func (o *BillKeyRecord) IsRevoked() bool {
+	return !o.RevokedAt.IsZero()
+}
+
+// additionalData 암호문을 다른 record 로 옮겨 복호화 하지 못하도록 id, 사용자, 결제 수단을 인증
+func (o *BillKeyRecord) additionalData() []byte {
+	return []byte(fmt.Sprintf("%s|%d|%s", o.ID, o.UserID, o.PgCode))
+}
+
+type IBillKeyStore interface {
+	// Create 새 billkey 저장, 같은 id 가 있으면 ErrBillKeyAlreadyExists
+	Create(record BillKeyRecord) error
+	// Get 없으면 ErrBillKeyNotFound
+	Get(id string) (BillKeyRecord, error)
+	// ListByUser 폐기한 billkey 포함, 등록 순
+	ListByUser(userID int64) ([]BillKeyRecord, error)
+	// ListNotEncryptedWith keyID 가 아닌 master key 로 암호화된, 폐기하지 않은 billkey 최대 limit 개
+	ListNotEncryptedWith(keyID string, limit int) ([]BillKeyRecord, error)
+	// UpdateEncryption 다시 암호화한 key, 암호문 저장
+	UpdateEncryption(record BillKeyRecord) error
+	// Revoke 폐기 시각 저장, 없으면 ErrBillKeyNotFound
+	Revoke(id string, at time.Time) error
+}
+
+// BillKeyVault billkey 를 AES-GCM 으로 암호화해서 저장
+type BillKeyVault struct {
+	Store IBillKeyStore
+	Keys  IKeyProvider
+	now   Clock
+}
+
+func NewBillKeyVault(store IBillKeyStore, keys IKeyProvider, opts ...Option) *BillKeyVault {
+	return &BillKeyVault{
+		Store: store,
+		Keys:  keys,
+		now:   newOptions(opts).clock,
+	}
+}
+
+// Put billkey 를 암호화해서 저장하고 record id 를 반환
+func (o *BillKeyVault) Put(metadata BillKeyMetadata, billKey string) (record BillKeyRecord, err error) {
+	if billKey == "" {
+		err = validate(rules(fieldError("billkey", "필수 값")))
+		return
+	}
+
+	now := o.now()
+	record = BillKeyRecord{
+		ID:              newEventID(),
+		BillKeyMetadata: metadata,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err = o.seal(&record, billKey); err != nil {
+		return
+	}
+	err = o.Store.Create(record)
+	return
+}
+
+// Reveal 복호화한 billkey, 폐기한 billkey 는 ErrBillKeyRevoked
+func (o *BillKeyVault) Reveal(id string) (billKey string, record BillKeyRecord, err error) {
+	if record, err = o.Store.Get(id); err != nil {
+		return
+	}
+	if record.IsRevoked() {
+		err = fmt.Errorf("%w: %s", ErrBillKeyRevoked, id)
+		return
+	}
+	billKey, err = o.open(record)
+	return
+}
+
+func (o *BillKeyVault) Get(id string) (BillKeyRecord, error) {
+	return o.Store.Get(id)
+}
+
+func (o *BillKeyVault) ListByUser(userID int64) ([]BillKeyRecord, error) {
+	return o.Store.ListByUser(userID)
+}
+
+// Revoke 다시 결제에 사용하지 않도록 폐기, 암호문은 감사 용도로 남김
+func (o *BillKeyVault) Revoke(id string) error {
+	return o.Store.Revoke(id, o.now())
+}
+
+// Rotate 현재 master key 가 아닌 key 로 암호화된 billkey 를 최대 limit 개 다시 암호화
+//
+// 복호화, 저장에 실패한 record 는 건너뛰고 나머지를 계속 처리, 실패한 record 의 에러를 모아 반환
+func (o *BillKeyVault) Rotate(limit int) (rotated int, err error) {
+	records, err := o.Store.ListNotEncryptedWith(o.Keys.CurrentKeyID(), limit)
+	if err != nil {
+		return
+	}
+
+	var errs []error
+	for _, record := range records {
+		if rotateErr := o.rotate(record); rotateErr != nil {
+			errs = append(errs, fmt.Errorf("[%s]%w", record.ID, rotateErr))
+			continue
+		}
+		rotated++
+	}
+	err = errors.Join(errs...)
+	return
+}
+
+func (o *BillKeyVault) rotate(record BillKeyRecord) (err error) {
+	billKey, err := o.open(record)
+	if err != nil {
+		return
+	}
+	if err = o.seal(&record, billKey); err != nil {
+		return
+	}
+	record.UpdatedAt = o.now()
+	return o.Store.UpdateEncryption(record)
+}
+
+func (o *BillKeyVault) seal(record *BillKeyRecord, billKey string) (err error) {
+	dataKey, err := o.Keys.GenerateDataKey()
+	if err != nil {
+		return
+	}
+
+	nonce, ciphertext, err := aesGCMSeal(dataKey.Plaintext, []byte(billKey), record.additionalData())
+	if err != nil {
+		return
+	}
+
+	record.KeyID = dataKey.KeyID
+	record.EncryptedDataKey = dataKey.Ciphertext
+	record.Nonce = nonce
+	record.Ciphertext = ciphertext
+	return
+}
+
+func (o *BillKeyVault) open(record BillKeyRecord) (billKey string, err error) {
+	dataKey, err := o.Keys.DecryptDataKey(record.KeyID, record.EncryptedDataKey)
+	if err != nil {
+		return
+	}
+
+	plaintext, err := aesGCMOpen(dataKey, record.Nonce, record.Ciphertext, record.additionalData())
+	if err != nil {
+		err = fmt.Errorf("[%s]billkey 복호화 실패: %w", record.ID, err)
+		return
+	}
+	return string(plaintext), nil
+}
+
+func aesGCMSeal(key, plaintext, additionalData []byte) (nonce, ciphertext []byte, err error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return
+	}
+
+	nonce = make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return
+	}
+	ciphertext = gcm.Seal(nil, nonce, plaintext, additionalData)
+	return
+}
+
+func aesGCMOpen(key, nonce, ciphertext, additionalData []byte) (plaintext []byte, err error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, errors.New("유효하지 않은 nonce")
+	}
+	return gcm.Open(nil, nonce, ciphertext, additionalData)
+}
+
+// StaticKeyProvider 메모리의 master key 로 data key 를 암호화, KMS 를 쓰지 않는 환경, 테스트용
+type StaticKeyProvider struct {
+	mu        sync.RWMutex
+	currentID string
+	keys      map[string][]byte
+}
+
+func NewStaticKeyProvider(keyID string, masterKey []byte) (*StaticKeyProvider, error) {
+	o := &StaticKeyProvider{
+		keys: make(map[string][]byte),
+	}
+	if err := o.Rotate(keyID, masterKey); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// Rotate 새 master key 를 추가하고 현재 key 로 사용, 이전 key 는 복호화용으로 유지
+func (o *StaticKeyProvider) Rotate(keyID string, masterKey []byte) error {
+	if keyID == "" {
+		return errors.New("key id 필수")
+	}
+	if len(masterKey) != dataKeySize {
+		return fmt.Errorf("master key 는 %d bytes 이어야 함", dataKeySize)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if existing, exists := o.keys[keyID]; exists && string(existing) != string(masterKey) {
+		return fmt.Errorf("key id %s 가 다른 key 로 이미 등록됨", keyID)
+	}
+	o.keys[keyID] = append([]byte(nil), masterKey...)
+	o.currentID = keyID
+	return nil
+}
+
+func (o *StaticKeyProvider) CurrentKeyID() string {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.currentID
+}
+
+func (o *StaticKeyProvider) GenerateDataKey() (dataKey DataKey, err error) {
+	o.mu.RLock()
+	keyID, masterKey := o.currentID, o.keys[o.currentID]
+	o.mu.RUnlock()
+
+	plaintext := make([]byte, dataKeySize)
+	if _, err = rand.Read(plaintext); err != nil {
+		return
+	}
+
+	nonce, ciphertext, err := aesGCMSeal(masterKey, plaintext, []byte(keyID))
+	if err != nil {
+		return
+	}
+
+	return DataKey{
+		KeyID:      keyID,
+		Plaintext:  plaintext,
+		Ciphertext: append(nonce, ciphertext...),
+	}, nil
+}
+
+func (o *StaticKeyProvider) DecryptDataKey(keyID string, ciphertext []byte) ([]byte, error) {
+	o.mu.RLock()
+	masterKey, exists := o.keys[keyID]
+	o.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+
+	const nonceSize = 12 // cipher.NewGCM 기본 nonce 크기
+	if len(ciphertext) < nonceSize {
+		return nil, errors.New("유효하지 않은 data key")
+	}
+	return aesGCMOpen(masterKey, ciphertext[:nonceSize], ciphertext[nonceSize:], []byte(keyID))
+}
+
+type MemoryBillKeyStore struct {
+	mu      sync.RWMutex
+	records map[string]BillKeyRecord
+}
+
+func NewMemoryBillKeyStore() *MemoryBillKeyStore {
+	return &MemoryBillKeyStore{
+		records: make(map[string]BillKeyRecord),
+	}
+}
+
+func (o *MemoryBillKeyStore) Create(record BillKeyRecord) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, exists := o.records[record.ID]; exists {
+		return ErrBillKeyAlreadyExists
+	}
+	o.records[record.ID] = record
+	return nil
+}
+
+func (o *MemoryBillKeyStore) Get(id string) (BillKeyRecord, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	record, exists := o.records[id]
+	if !exists {
+		return BillKeyRecord{}, ErrBillKeyNotFound
+	}
+	return record, nil
+}
+
+func (o *MemoryBillKeyStore) ListByUser(userID int64) ([]BillKeyRecord, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	records := make([]BillKeyRecord, 0)
+	for _, record := range o.records {
+		if record.UserID == userID {
+			records = append(records, record)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].CreatedAt.Before(records[j].CreatedAt)
+	})
+	return records, nil
+}
+
+func (o *MemoryBillKeyStore) ListNotEncryptedWith(keyID string, limit int) ([]BillKeyRecord, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	records := make([]BillKeyRecord, 0)
+	for _, record := range o.records {
+		if limit > 0 && len(records) >= limit {
+			break
+		}
+		if record.KeyID != keyID && !record.IsRevoked() {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func (o *MemoryBillKeyStore) UpdateEncryption(record BillKeyRecord) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	stored, exists := o.records[record.ID]
+	if !exists {
+		return ErrBillKeyNotFound
+	}
+	stored.KeyID = record.KeyID
+	stored.EncryptedDataKey = record.EncryptedDataKey
+	stored.Nonce = record.Nonce
+	stored.Ciphertext = record.Ciphertext
+	stored.UpdatedAt = record.UpdatedAt
+	o.records[record.ID] = stored
+	return nil
+}
+
+func (o *MemoryBillKeyStore) Revoke(id string, at time.Time) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	stored, exists := o.records[id]
+	if !exists {
+		return ErrBillKeyNotFound
+	}
+	if !stored.IsRevoked() {
+		stored.RevokedAt = at
+		stored.UpdatedAt = at
+		o.records[id] = stored
+	}
+	return nil
+}
+
+// BillKeyTableDDL SQLBillKeyStore 용 테이블 (MySQL 기준)
+const BillKeyTableDDL = `CREATE TABLE payletter_billkey (
+	id                 CHAR(32)     NOT NULL PRIMARY KEY,
+	user_id            BIGINT       NOT NULL,
+	pgcode             VARCHAR(32)  NOT NULL,
+	card_code          VARCHAR(16)  NOT NULL,
+	card_info          VARCHAR(64)  NOT NULL,
+	alias_name         VARCHAR(100) NOT NULL,
+	key_id             VARCHAR(191) NOT NULL,
+	encrypted_data_key BLOB         NOT NULL,
+	nonce              VARBINARY(32) NOT NULL,
+	ciphertext         BLOB         NOT NULL,
+	created_at         BIGINT       NOT NULL,
+	updated_at         BIGINT       NOT NULL,
+	revoked_at         BIGINT       NOT NULL DEFAULT 0,
+	INDEX idx_payletter_billkey_user (user_id),
+	INDEX idx_payletter_billkey_key (key_id)
+)`
+
+// SQLBillKeyStore database/sql 기반 저장소, placeholder 는 ? 를 사용 (MySQL, SQLite)
+type SQLBillKeyStore struct {
+	DB    *sql.DB
+	Table string // 비어 있으면 payletter_billkey
+}
+
+func NewSQLBillKeyStore(db *sql.DB) *SQLBillKeyStore {
+	return &SQLBillKeyStore{
+		DB: db,
+	}
+}
+
+func (o *SQLBillKeyStore) table() string {
+	if o.Table == "" {
+		return "payletter_billkey"
+	}
+	return o.Table
+}
+
+const billKeyColumns = "id, user_id, pgcode, card_code, card_info, alias_name, key_id, encrypted_data_key, nonce, ciphertext, created_at, updated_at, revoked_at"
+
+func (o *SQLBillKeyStore) Create(record BillKeyRecord) (err error) {
+	if _, found, getErr := o.get(record.ID); getErr == nil && found {
+		return ErrBillKeyAlreadyExists
+	}
+
+	_, err = o.DB.Exec(
+		fmt.Sprintf("INSERT INTO %s (%s) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", o.table(), billKeyColumns),
+		record.ID, record.UserID, record.PgCode.String(), record.CardCode, record.CardInfo, record.AliasName,
+		record.KeyID, record.EncryptedDataKey, record.Nonce, record.Ciphertext,
+		record.CreatedAt.UnixMilli(), record.UpdatedAt.UnixMilli(), unixMilliOrZero(record.RevokedAt),
+	)
+	return
+}
+
+func (o *SQLBillKeyStore) Get(id string) (record BillKeyRecord, err error) {
+	record, found, err := o.get(id)
+	if err == nil && !found {
+		err = ErrBillKeyNotFound
+	}
+	return
+}
+
+func (o *SQLBillKeyStore) get(id string) (record BillKeyRecord, found bool, err error) {
+	records, err := o.query(fmt.Sprintf("SELECT %s FROM %s WHERE id = ?", billKeyColumns, o.table()), id)
+	if err != nil || len(records) == 0 {
+		return
+	}
+	return records[0], true, nil
+}
+
+func (o *SQLBillKeyStore) ListByUser(userID int64) ([]BillKeyRecord, error) {
+	return o.query(fmt.Sprintf("SELECT %s FROM %s WHERE user_id = ? ORDER BY created_at", billKeyColumns, o.table()), userID)
+}
+
+func (o *SQLBillKeyStore) ListNotEncryptedWith(keyID string, limit int) ([]BillKeyRecord, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	return o.query(
+		fmt.Sprintf("SELECT %s FROM %s WHERE key_id <> ? AND revoked_at = 0 LIMIT ?", billKeyColumns, o.table()),
+		keyID, limit,
+	)
+}
+
+func (o *SQLBillKeyStore) query(query string, args ...any) (records []BillKeyRecord, err error) {
+	rows, err := o.DB.Query(query, args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	records = make([]BillKeyRecord, 0)
+	for rows.Next() {
+		var (
+			record                          BillKeyRecord
+			pgCode                          string
+			createdAt, updatedAt, revokedAt int64
+		)
+		if err = rows.Scan(
+			&record.ID, &record.UserID, &pgCode, &record.CardCode, &record.CardInfo, &record.AliasName,
+			&record.KeyID, &record.EncryptedDataKey, &record.Nonce, &record.Ciphertext,
+			&createdAt, &updatedAt, &revokedAt,
+		); err != nil {
+			return
+		}
+
+		record.PgCode = PgCode(pgCode)
+		record.CreatedAt = time.UnixMilli(createdAt)
+		record.UpdatedAt = time.UnixMilli(updatedAt)
+		if revokedAt > 0 {
+			record.RevokedAt = time.UnixMilli(revokedAt)
+		}
+		records = append(records, record)
+	}
+	err = rows.Err()
+	return
+}
+
+func (o *SQLBillKeyStore) UpdateEncryption(record BillKeyRecord) error {
+	return o.exec(
+		fmt.Sprintf("UPDATE %s SET key_id = ?, encrypted_data_key = ?, nonce = ?, ciphertext = ?, updated_at = ? WHERE id = ?", o.table()),
+		record.KeyID, record.EncryptedDataKey, record.Nonce, record.Ciphertext, record.UpdatedAt.UnixMilli(), record.ID,
+	)
+}
+
+func (o *SQLBillKeyStore) Revoke(id string, at time.Time) error {
+	if _, err := o.Get(id); err != nil {
+		return err
+	}
+	_, err := o.DB.Exec(
+		fmt.Sprintf("UPDATE %s SET revoked_at = ?, updated_at = ? WHERE id = ? AND revoked_at = 0", o.table()),
+		at.UnixMilli(), at.UnixMilli(), id,
+	)
+	return err
+}
+
+// exec 변경된 행이 없으면 ErrBillKeyNotFound
+func (o *SQLBillKeyStore) exec(query string, args ...any) error {
+	result, err := o.DB.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrBillKeyNotFound
+	}
+	return nil
+}
+
+func unixMilliOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
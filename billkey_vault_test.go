@@ -0,0 +1,127 @@
+package payletter
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func newTestVault(t *testing.T) (*BillKeyVault, *StaticKeyProvider, *MemoryBillKeyStore) {
+	t.Helper()
+	keys, err := NewStaticKeyProvider("key-1", bytes.Repeat([]byte{1}, dataKeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := NewMemoryBillKeyStore()
+	return NewBillKeyVault(store, keys), keys, store
+}
+
+func TestBillKeyVaultRoundTrip(t *testing.T) {
+	vault, _, store := newTestVault(t)
+	metadata := BillKeyMetadata{UserID: 1, PgCode: PgCodeCreditCard, CardInfo: "1234-****-****-5678"}
+
+	record, err := vault.Put(metadata, "BILLKEY-SECRET")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, _ := store.Get(record.ID)
+	if bytes.Contains(stored.Ciphertext, []byte("BILLKEY-SECRET")) {
+		t.Fatal("평문이 저장됨")
+	}
+
+	billKey, revealed, err := vault.Reveal(record.ID)
+	if err != nil || billKey != "BILLKEY-SECRET" || revealed.CardInfo != metadata.CardInfo {
+		t.Fatalf("billkey %q, err %v", billKey, err)
+	}
+
+	if err = vault.Revoke(record.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = vault.Reveal(record.ID); !errors.Is(err, ErrBillKeyRevoked) {
+		t.Fatalf("err = %v, want ErrBillKeyRevoked", err)
+	}
+
+	if _, err = vault.Put(metadata, ""); !errors.Is(err, ErrValidation) {
+		t.Fatalf("빈 billkey err = %v", err)
+	}
+}
+
+func TestBillKeyVaultDetectsTampering(t *testing.T) {
+	vault, _, store := newTestVault(t)
+	first, _ := vault.Put(BillKeyMetadata{UserID: 1, PgCode: PgCodeCreditCard}, "BILLKEY-1")
+	second, _ := vault.Put(BillKeyMetadata{UserID: 2, PgCode: PgCodeCreditCard}, "BILLKEY-2")
+
+	// 다른 사용자의 암호문을 옮겨 붙이면 AAD 가 달라 복호화 실패
+	moved := second
+	moved.KeyID, moved.EncryptedDataKey, moved.Nonce, moved.Ciphertext = first.KeyID, first.EncryptedDataKey, first.Nonce, first.Ciphertext
+	if err := store.UpdateEncryption(moved); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := vault.Reveal(second.ID); err == nil {
+		t.Fatal("옮겨 붙인 암호문이 복호화됨")
+	}
+
+	// 암호문 변조
+	tampered := first
+	tampered.Ciphertext = append([]byte(nil), first.Ciphertext...)
+	tampered.Ciphertext[0] ^= 0xff
+	_ = store.UpdateEncryption(tampered)
+	if _, _, err := vault.Reveal(first.ID); err == nil || !strings.Contains(err.Error(), first.ID) {
+		t.Fatalf("변조된 암호문 err = %v", err)
+	}
+}
+
+func TestBillKeyVaultRotate(t *testing.T) {
+	vault, keys, store := newTestVault(t)
+	record, _ := vault.Put(BillKeyMetadata{UserID: 1, PgCode: PgCodeCreditCard}, "BILLKEY-1")
+
+	if err := keys.Rotate("key-2", bytes.Repeat([]byte{2}, dataKeySize)); err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := vault.Rotate(10)
+	if err != nil || rotated != 1 {
+		t.Fatalf("rotated %d, err %v", rotated, err)
+	}
+
+	stored, _ := store.Get(record.ID)
+	if stored.KeyID != "key-2" {
+		t.Fatalf("key id %s, want key-2", stored.KeyID)
+	}
+	if billKey, _, err := vault.Reveal(record.ID); err != nil || billKey != "BILLKEY-1" {
+		t.Fatalf("billkey %q, err %v", billKey, err)
+	}
+	if rotated, _ = vault.Rotate(10); rotated != 0 {
+		t.Fatalf("다시 암호화 %d 건", rotated)
+	}
+
+	if err = keys.Rotate("key-2", bytes.Repeat([]byte{3}, dataKeySize)); err == nil {
+		t.Fatal("같은 key id 에 다른 key 등록")
+	}
+	if _, err = keys.DecryptDataKey("key-9", stored.EncryptedDataKey); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("err = %v, want ErrUnknownKey", err)
+	}
+}
+
+func TestBillKeyVaultRotateSkipsBrokenRecords(t *testing.T) {
+	vault, keys, store := newTestVault(t)
+	broken, _ := vault.Put(BillKeyMetadata{UserID: 1, PgCode: PgCodeCreditCard}, "BILLKEY-1")
+	record, _ := vault.Put(BillKeyMetadata{UserID: 2, PgCode: PgCodeCreditCard}, "BILLKEY-2")
+
+	// 알 수 없는 master key 로 암호화된 record
+	broken.KeyID = "key-9"
+	if err := store.UpdateEncryption(broken); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := keys.Rotate("key-2", bytes.Repeat([]byte{2}, dataKeySize)); err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := vault.Rotate(10)
+	if rotated != 1 || !errors.Is(err, ErrUnknownKey) || !strings.Contains(err.Error(), broken.ID) {
+		t.Fatalf("rotated %d, err %v", rotated, err)
+	}
+	if billKey, _, err := vault.Reveal(record.ID); err != nil || billKey != "BILLKEY-2" {
+		t.Fatalf("billkey %q, err %v", billKey, err)
+	}
+}
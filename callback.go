@@ -0,0 +1,113 @@
+package payletter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrCallbackReplay = errors.New("이미 처리된 TID 에 다른 결제 정보로 callback")
+
+const (
+	defaultCallbackLockTTL = time.Minute
+	callbackCompleteTries  = 3
+)
+
+// CallbackProcessor ReturnUrl, CallbackUrl 로 오는 결제 결과를 TID 당 한번만 처리
+//
+// 같은 결제 정보의 재전송은 handler 를 호출하지 않고 성공으로 응답하고,
+// payhash 는 유효하지만 이미 처리한 TID 와 결제 정보가 다르면 ErrCallbackReplay.
+// 처리 완료를 저장하지 못한 채 잠금이 만료되면 재전송으로 handler 가 다시 실행될 수 있으므로 handler 는 TID 기준으로 멱등해야 함
+type CallbackProcessor struct {
+	PaymentAPIKey string
+	Store         IIdempotencyStore
+	Handler       func(ctx context.Context, data ResPaymentData) error
+	LockTTL       time.Duration                        // 잠금 유지 시간, handler 실행 중에는 LockTTL/2 마다 연장, 0 이면 1분
+	OnError       func(data ResPaymentData, err error) // handler 성공 후 처리 완료를 저장하지 못했을 때 호출 (로그, metrics 용)
+}
+
+func NewCallbackProcessor(paymentAPIKey string, store IIdempotencyStore, handler func(ctx context.Context, data ResPaymentData) error) *CallbackProcessor {
+	return &CallbackProcessor{
+		PaymentAPIKey: paymentAPIKey,
+		Store:         store,
+		Handler:       handler,
+	}
+}
+
+// Process duplicate = true 이면 이미 처리된 callback 의 재전송
+//
+// handler 가 실패하거나 panic 이면 TID 를 반납해 페이레터의 재전송으로 다시 처리할 수 있게 함.
+// 다른 요청이 같은 TID 를 처리 중이면 ErrIdempotencyInProgress
+func (o *CallbackProcessor) Process(ctx context.Context, data ResPaymentData) (duplicate bool, err error) {
+	if err = data.Validate(o.PaymentAPIKey); err != nil {
+		err = fmt.Errorf("%w: [%s]%v", ErrInvalidCallback, data.OrderNo, err)
+		return
+	}
+	if data.Tid == "" {
+		err = fmt.Errorf("%w: [%s]tid 없음", ErrInvalidCallback, data.OrderNo)
+		return
+	}
+
+	lockTTL := o.LockTTL
+	if lockTTL <= 0 {
+		lockTTL = defaultCallbackLockTTL
+	}
+
+	key := "callback/" + data.Tid
+	_, acquired, err := o.Store.Acquire(key, callbackFingerprint(data), lockTTL)
+	if errors.Is(err, ErrIdempotencyKeyMismatch) {
+		err = fmt.Errorf("%w: [%s]%s", ErrCallbackReplay, data.OrderNo, data.Tid)
+		return
+	}
+	if err != nil {
+		return
+	}
+	if !acquired {
+		return true, nil
+	}
+
+	handled := false
+	defer func() {
+		// handler 가 panic 이면 TID 를 반납해 재전송으로 다시 처리할 수 있게 함
+		if !handled {
+			_ = o.Store.Release(key)
+		}
+	}()
+
+	stop := keepLocked(o.Store, key, lockTTL)
+	defer stop()
+
+	err = o.Handler(ctx, data)
+	handled = true
+	stop()
+
+	if err != nil {
+		if releaseErr := o.Store.Release(key); releaseErr != nil {
+			err = errors.Join(err, releaseErr)
+		}
+		return
+	}
+
+	// handler 는 성공했으므로 저장에 실패해도 성공으로 응답하고 OnError 로 알림,
+	// 잠금이 남아 있는 동안은 재전송도 handler 를 실행하지 않음
+	var completeErr error
+	for i := 0; i < callbackCompleteTries; i++ {
+		if completeErr = o.Store.Complete(key, nil, "", ""); completeErr == nil {
+			break
+		}
+	}
+	if completeErr != nil && o.OnError != nil {
+		o.OnError(data, fmt.Errorf("[%s]callback 처리 완료 저장 실패: %w", data.Tid, completeErr))
+	}
+	return
+}
+
+// callbackFingerprint 결제를 식별하는 field 의 hash, ReturnUrl 과 CallbackUrl 에 따라 다를 수 있는 표시용 field 는 제외
+func callbackFingerprint(data ResPaymentData) string {
+	text := fmt.Sprintf("%s|%s|%s|%s|%d|%s|%s", data.Tid, data.Cid, data.OrderNo, data.UserID, data.Amount, data.PgCode, data.BillKey)
+	h := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(h[:])
+}
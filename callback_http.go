@@ -0,0 +1,266 @@
+package payletter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const defaultCallbackMaxBodyBytes = 64 << 10
+
+var ErrEmptyAllowlist = errors.New("callback 을 허용할 IP 대역 없음")
+
+type ICallbackMetrics interface {
+	// Rejected 거부한 callback, reason 은 CallbackReject 값
+	Rejected(reason string)
+	Accepted(duplicate bool)
+}
+
+// CallbackHandler 페이레터 ReturnUrl, CallbackUrl 용 http.Handler
+//
+// 허용한 IP 에서 온 POST 만 CallbackProcessor 로 넘김
+type CallbackHandler struct {
+	Processor *CallbackProcessor
+	// AllowedNets 페이레터 callback 발신 IP 대역, 비어 있으면 모든 요청을 거부
+	AllowedNets []*net.IPNet
+	// AllowAnyIP IP 를 검사하지 않음 (로컬 개발, 앞단에서 IP 를 검사하는 경우), payhash 만으로 검증
+	AllowAnyIP bool
+	// TrustedProxies 이 대역에서 온 요청은 ClientIPHeader 로 원래 IP 를 찾음 (load balancer, reverse proxy)
+	TrustedProxies []*net.IPNet
+	ClientIPHeader string // 비어 있으면 X-Forwarded-For
+	MaxBodyBytes   int64  // 0 이면 64KB
+	Metrics        ICallbackMetrics
+}
+
+// NewCallbackHandler allowedCIDRs 가 비어 있으면 ErrEmptyAllowlist, IP 를 검사하지 않으려면 AllowAnyIP 를 직접 설정
+func NewCallbackHandler(processor *CallbackProcessor, allowedCIDRs, trustedProxyCIDRs []string) (*CallbackHandler, error) {
+	allowed, err := ParseCIDRs(allowedCIDRs)
+	if err != nil {
+		return nil, err
+	}
+	if len(allowed) == 0 {
+		return nil, ErrEmptyAllowlist
+	}
+	trusted, err := ParseCIDRs(trustedProxyCIDRs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CallbackHandler{
+		Processor:      processor,
+		AllowedNets:    allowed,
+		TrustedProxies: trusted,
+	}, nil
+}
+
+// ParseCIDRs "1.2.3.0/24" 형식, 단일 IP 는 /32 (IPv6 는 /128) 로 처리
+func ParseCIDRs(cidrs []string) (nets []*net.IPNet, err error) {
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if !strings.Contains(cidr, "/") {
+			if ip := net.ParseIP(cidr); ip != nil && ip.To4() != nil {
+				cidr += "/32"
+			} else {
+				cidr += "/128"
+			}
+		}
+
+		_, ipNet, parseErr := net.ParseCIDR(cidr)
+		if parseErr != nil {
+			return nil, fmt.Errorf("유효하지 않은 IP 대역 %q: %w", cidr, parseErr)
+		}
+		nets = append(nets, ipNet)
+	}
+	return
+}
+
+func (o *CallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		o.reject(w, CallbackReject.Method, http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !o.AllowAnyIP && !containsIP(o.AllowedNets, o.ClientIP(r)) {
+		o.reject(w, CallbackReject.IP, http.StatusForbidden)
+		return
+	}
+
+	maxBodyBytes := o.MaxBodyBytes
+	if maxBodyBytes <= 0 {
+		maxBodyBytes = defaultCallbackMaxBodyBytes
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+
+	data, err := decodeCallback(r)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			o.reject(w, CallbackReject.Size, http.StatusRequestEntityTooLarge)
+			return
+		}
+		o.reject(w, CallbackReject.Malformed, http.StatusBadRequest)
+		return
+	}
+
+	duplicate, err := o.Processor.Process(r.Context(), data)
+	switch {
+	case err == nil:
+		if o.Metrics != nil {
+			o.Metrics.Accepted(duplicate)
+		}
+		w.WriteHeader(http.StatusOK)
+	case errors.Is(err, ErrInvalidCallback):
+		o.reject(w, CallbackReject.Hash, http.StatusUnauthorized)
+	case errors.Is(err, ErrCallbackReplay):
+		o.reject(w, CallbackReject.Replay, http.StatusConflict)
+	case errors.Is(err, ErrIdempotencyInProgress):
+		// 다른 요청이 처리 중, 페이레터 재전송 시 다시 확인
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+	default:
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
+
+// ClientIP 신뢰하는 proxy 를 거친 요청이면 header 의 오른쪽부터 신뢰하지 않는 첫 IP
+func (o *CallbackHandler) ClientIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !containsIP(o.TrustedProxies, ip) {
+		return ip
+	}
+
+	header := o.ClientIPHeader
+	if header == "" {
+		header = "X-Forwarded-For"
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values(header), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := net.ParseIP(strings.TrimSpace(hops[i]))
+		if hop == nil {
+			return ip // 형식이 잘못된 header 는 무시하고 마지막 proxy 를 client 로 봄
+		}
+		ip = hop
+		if !containsIP(o.TrustedProxies, hop) {
+			break
+		}
+	}
+	return ip
+}
+
+func (o *CallbackHandler) reject(w http.ResponseWriter, reason string, status int) {
+	if o.Metrics != nil {
+		o.Metrics.Rejected(reason)
+	}
+	http.Error(w, http.StatusText(status), status)
+}
+
+func containsIP(nets []*net.IPNet, ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeCallback json 또는 form 형식의 결제 결과
+func decodeCallback(r *http.Request) (data ResPaymentData, err error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "application/json" {
+		err = json.NewDecoder(r.Body).Decode(&data)
+		return
+	}
+
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	err = decodeCallbackForm(r.PostForm, &data)
+	return
+}
+
+// decodeCallbackForm form tag (없으면 json tag) 이름으로 최상위 문자열, 정수 field 를 채움
+func decodeCallbackForm(form map[string][]string, data *ResPaymentData) error {
+	v := reflect.ValueOf(data).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("form"), ",")[0]
+		if name == "" {
+			name = strings.Split(field.Tag.Get("json"), ",")[0]
+		}
+		values, exists := form[name]
+		if !exists || len(values) == 0 {
+			continue
+		}
+
+		switch field.Type.Kind() {
+		case reflect.String:
+			v.Field(i).SetString(values[0])
+		case reflect.Int:
+			if values[0] == "" {
+				continue
+			}
+			n, err := strconv.Atoi(values[0])
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			v.Field(i).SetInt(int64(n))
+		}
+	}
+	return nil
+}
+
+// MemoryCallbackMetrics 메모리 counter, metrics 수집기에 연결하기 전이나 테스트용
+type MemoryCallbackMetrics struct {
+	mu         sync.Mutex
+	rejected   map[string]int
+	accepted   int
+	duplicates int
+}
+
+func NewMemoryCallbackMetrics() *MemoryCallbackMetrics {
+	return &MemoryCallbackMetrics{
+		rejected: make(map[string]int),
+	}
+}
+
+func (o *MemoryCallbackMetrics) Rejected(reason string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.rejected[reason]++
+}
+
+func (o *MemoryCallbackMetrics) Accepted(duplicate bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.accepted++
+	if duplicate {
+		o.duplicates++
+	}
+}
+
+// Snapshot 거부 사유별 횟수와 처리 횟수 (재전송 포함)
+func (o *MemoryCallbackMetrics) Snapshot() (rejected map[string]int, accepted, duplicates int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	rejected = make(map[string]int, len(o.rejected))
+	for reason, count := range o.rejected {
+		rejected[reason] = count
+	}
+	return rejected, o.accepted, o.duplicates
+}
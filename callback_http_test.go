@@ -0,0 +1,135 @@
+package payletter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func newTestCallbackHandler(t *testing.T, allowed []string) (*CallbackHandler, *MemoryCallbackMetrics) {
+	t.Helper()
+	processor := NewCallbackProcessor(testPaymentAPIKey, NewMemoryIdempotencyStore(), func(ctx context.Context, data ResPaymentData) error {
+		return nil
+	})
+	handler, err := NewCallbackHandler(processor, allowed, []string{"10.0.0.0/8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	metrics := NewMemoryCallbackMetrics()
+	handler.Metrics = metrics
+	return handler, metrics
+}
+
+func callbackRequest(remoteAddr string, data ResPaymentData) *http.Request {
+	form := url.Values{
+		"user_id": {data.UserID},
+		"tid":     {data.Tid},
+		"amount":  {strconv.Itoa(data.Amount)},
+		"pgcode":  {data.PgCode.String()},
+		"payhash": {data.PayHash},
+	}
+	r := httptest.NewRequest(http.MethodPost, "/payletter/callback", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.RemoteAddr = remoteAddr
+	return r
+}
+
+func TestNewCallbackHandlerRequiresAllowlist(t *testing.T) {
+	if _, err := NewCallbackHandler(nil, nil, nil); !errors.Is(err, ErrEmptyAllowlist) {
+		t.Fatalf("err = %v, want ErrEmptyAllowlist", err)
+	}
+	if _, err := NewCallbackHandler(nil, []string{"not-an-ip"}, nil); err == nil {
+		t.Fatal("잘못된 IP 대역 허용")
+	}
+}
+
+func TestCallbackHandlerIPAllowlist(t *testing.T) {
+	handler, metrics := newTestCallbackHandler(t, []string{"203.0.113.0/24"})
+	data := signedCallback("tid-1", 1000)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		status     int
+	}{
+		{"direct allowed", "203.0.113.10:1234", "", http.StatusOK},
+		{"direct denied", "198.51.100.1:1234", "", http.StatusForbidden},
+		{"via trusted proxy", "10.0.0.1:1234", "203.0.113.10", http.StatusOK},
+		{"spoofed header from untrusted peer", "198.51.100.1:1234", "203.0.113.10", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := callbackRequest(tt.remoteAddr, data)
+			if tt.forwarded != "" {
+				r.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if w.Code != tt.status {
+				t.Fatalf("status %d, want %d", w.Code, tt.status)
+			}
+		})
+	}
+
+	rejected, accepted, duplicates := metrics.Snapshot()
+	if rejected[CallbackReject.IP] != 2 || accepted != 2 || duplicates != 1 {
+		t.Fatalf("rejected %v, accepted %d, duplicates %d", rejected, accepted, duplicates)
+	}
+}
+
+func TestCallbackHandlerEmptyAllowlistRejects(t *testing.T) {
+	handler, _ := newTestCallbackHandler(t, []string{"203.0.113.0/24"})
+	handler.AllowedNets = nil
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, callbackRequest("203.0.113.10:1234", signedCallback("tid-1", 1000)))
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("status %d, want %d", w.Code, http.StatusForbidden)
+	}
+
+	handler.AllowAnyIP = true
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, callbackRequest("198.51.100.1:1234", signedCallback("tid-1", 1000)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("AllowAnyIP status %d", w.Code)
+	}
+}
+
+func TestCallbackHandlerRejects(t *testing.T) {
+	handler, metrics := newTestCallbackHandler(t, []string{"203.0.113.0/24"})
+	handler.MaxBodyBytes = 256
+
+	forged := signedCallback("tid-1", 1000)
+	forged.Amount = 10
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, callbackRequest("203.0.113.10:1234", forged))
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("위조 payhash status %d", w.Code)
+	}
+
+	big := signedCallback("tid-2", 1000)
+	big.UserID = strings.Repeat("1", 512)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, callbackRequest("203.0.113.10:1234", big))
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("큰 body status %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/payletter/callback", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("GET status %d", w.Code)
+	}
+
+	rejected, _, _ := metrics.Snapshot()
+	if rejected[CallbackReject.Hash] != 1 || rejected[CallbackReject.Size] != 1 || rejected[CallbackReject.Method] != 1 {
+		t.Fatalf("rejected %v", rejected)
+	}
+}
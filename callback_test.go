@@ -0,0 +1,174 @@
+package payletter
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testPaymentAPIKey = "KEY"
+
+func signedCallback(tid string, amount int) ResPaymentData {
+	data := ResPaymentData{UserID: "100", OrderNo: "order-" + tid, Tid: tid, Amount: amount, PgCode: PgCodeCreditCard}
+	data.PayHash = NewSigner("", testPaymentAPIKey).Sign(HashRecipePaymentCallback, HashParams{UserID: data.UserID, Amount: data.Amount, TID: data.Tid})
+	return data
+}
+
+func TestCallbackProcessorRunsHandlerOnce(t *testing.T) {
+	var calls int32
+	processor := NewCallbackProcessor(testPaymentAPIKey, NewMemoryIdempotencyStore(), func(ctx context.Context, data ResPaymentData) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	})
+
+	data := signedCallback("tid-1", 1000)
+	for i := 0; i < 3; i++ {
+		duplicate, err := processor.Process(context.Background(), data)
+		if err != nil || duplicate != (i > 0) {
+			t.Fatalf("%d: duplicate %v, err %v", i, duplicate, err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("handler %d 회 실행", calls)
+	}
+
+	// payhash 는 유효하지만 같은 TID 에 다른 금액
+	if _, err := processor.Process(context.Background(), signedCallback("tid-1", 10)); !errors.Is(err, ErrCallbackReplay) {
+		t.Fatalf("err = %v, want ErrCallbackReplay", err)
+	}
+
+	forged := signedCallback("tid-2", 1000)
+	forged.Amount = 10
+	if _, err := processor.Process(context.Background(), forged); !errors.Is(err, ErrInvalidCallback) {
+		t.Fatalf("err = %v, want ErrInvalidCallback", err)
+	}
+}
+
+func TestCallbackProcessorRetriesFailedHandler(t *testing.T) {
+	fail := true
+	processor := NewCallbackProcessor(testPaymentAPIKey, NewMemoryIdempotencyStore(), func(ctx context.Context, data ResPaymentData) error {
+		if fail {
+			return errors.New("db down")
+		}
+		return nil
+	})
+
+	data := signedCallback("tid-1", 1000)
+	if _, err := processor.Process(context.Background(), data); err == nil {
+		t.Fatal("handler 에러가 전달되지 않음")
+	}
+	fail = false
+	if duplicate, err := processor.Process(context.Background(), data); err != nil || duplicate {
+		t.Fatalf("재전송: duplicate %v, err %v", duplicate, err)
+	}
+}
+
+func TestCallbackProcessorKeepsLockWhileHandlerRuns(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var calls int32
+
+	processor := NewCallbackProcessor(testPaymentAPIKey, NewMemoryIdempotencyStore(), func(ctx context.Context, data ResPaymentData) error {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(started)
+			<-release
+		}
+		return nil
+	})
+	processor.LockTTL = 40 * time.Millisecond
+
+	data := signedCallback("tid-1", 1000)
+	done := make(chan error, 1)
+	go func() {
+		_, err := processor.Process(context.Background(), data)
+		done <- err
+	}()
+
+	<-started
+	time.Sleep(3 * processor.LockTTL) // 연장하지 않으면 잠금이 만료되는 시간
+	if _, err := processor.Process(context.Background(), data); !errors.Is(err, ErrIdempotencyInProgress) {
+		t.Fatalf("처리 중 재전송 err = %v, want ErrIdempotencyInProgress", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Fatalf("handler %d 회 실행", calls)
+	}
+}
+
+// failingCompleteStore Complete 만 실패하는 저장소
+type failingCompleteStore struct {
+	*MemoryIdempotencyStore
+	completes int
+}
+
+func (o *failingCompleteStore) Complete(key string, result []byte, errCode, errMessage string) error {
+	o.completes++
+	return errors.New("write timeout")
+}
+
+func TestCallbackProcessorCompleteFailureIsSuccess(t *testing.T) {
+	store := &failingCompleteStore{MemoryIdempotencyStore: NewMemoryIdempotencyStore()}
+	var calls int32
+	processor := NewCallbackProcessor(testPaymentAPIKey, store, func(ctx context.Context, data ResPaymentData) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	})
+	var reported []error
+	processor.OnError = func(data ResPaymentData, err error) {
+		reported = append(reported, err)
+	}
+
+	data := signedCallback("tid-1", 1000)
+	if _, err := processor.Process(context.Background(), data); err != nil {
+		t.Fatalf("handler 성공 후 err = %v", err)
+	}
+	if store.completes != callbackCompleteTries {
+		t.Fatalf("Complete %d 회 시도", store.completes)
+	}
+	if len(reported) != 1 {
+		t.Fatalf("OnError %d 회 호출", len(reported))
+	}
+
+	// 잠금이 남아 있으므로 재전송이 handler 를 다시 실행하지 않음
+	if _, err := processor.Process(context.Background(), data); !errors.Is(err, ErrIdempotencyInProgress) {
+		t.Fatalf("err = %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("handler %d 회 실행", calls)
+	}
+}
+
+func TestCallbackProcessorReleasesOnPanic(t *testing.T) {
+	store := NewMemoryIdempotencyStore()
+	panicking := true
+	processor := NewCallbackProcessor(testPaymentAPIKey, store, func(ctx context.Context, data ResPaymentData) error {
+		if panicking {
+			panic("nil map")
+		}
+		return nil
+	})
+	processor.LockTTL = 20 * time.Millisecond
+
+	data := signedCallback("tid-1", 1000)
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("panic 이 전달되지 않음")
+			}
+		}()
+		_, _ = processor.Process(context.Background(), data)
+	}()
+
+	// 잠금 연장이 멈추고 TID 가 반납되어 재전송을 바로 처리
+	time.Sleep(2 * processor.LockTTL)
+	panicking = false
+	if duplicate, err := processor.Process(context.Background(), data); err != nil || duplicate {
+		t.Fatalf("panic 이후 재전송: duplicate %v, err %v", duplicate, err)
+	}
+}
@@ -0,0 +1,157 @@
+package payletter
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+var ErrNoEasyPayMethod = errors.New("결제 가능한 간편결제 수단 없음")
+
+// CheckoutAttempt 간편결제 수단 하나로 결제 시도한 결과
+type CheckoutAttempt struct {
+	BillKey       string `json:"billkey"`
+	PaymentMethod PgCode `json:"payment_method"`
+	Err           error  `json:"-"`
+}
+
+// CheckoutError 모든 결제 수단이 실패
+type CheckoutError struct {
+	UserID   int
+	Attempts []CheckoutAttempt
+}
+
+func (e *CheckoutError) Error() string {
+	if len(e.Attempts) == 0 {
+		return fmt.Sprintf("[%d]%s", e.UserID, ErrNoEasyPayMethod.Error())
+	}
+	last := e.Attempts[len(e.Attempts)-1]
+	return fmt.Sprintf("[%d]결제 수단 %d 개 모두 실패, 마지막 %s: %v", e.UserID, len(e.Attempts), last.BillKey, last.Err)
+}
+
+// Unwrap 마지막 시도의 에러, 시도한 결제 수단이 없으면 ErrNoEasyPayMethod
+func (e *CheckoutError) Unwrap() error {
+	if len(e.Attempts) == 0 {
+		return ErrNoEasyPayMethod
+	}
+	return e.Attempts[len(e.Attempts)-1].Err
+}
+
+type ResCheckout struct {
+	Method   EasyPayMethod     `json:"method"`
+	Response ResEasyPayUI      `json:"response"`
+	Attempts []CheckoutAttempt `json:"attempts"` // 성공한 시도 포함
+}
+
+// OneClickCheckout 결제 수단 선택 화면 없이 등록한 간편결제 수단으로 결제
+//
+// 즐겨찾기 결제 수단, 없으면 가장 최근에 결제한 수단을 먼저 시도하고 실패하면 MethodOrder 순서로 다른 수단을 시도
+type OneClickCheckout struct {
+	PayLetter IPayLetter
+
+	// MethodOrder 사용할 결제 수단 종류와 실패 시 시도 순서, 비어 있으면 모든 종류를 최근 결제 순으로 시도
+	MethodOrder []PgCode
+	// MaxAttempts 시도할 최대 결제 수단 수, 0 이면 제한 없음
+	MaxAttempts int
+	// ShouldFallback 페이레터가 거절한 결제 후 다음 결제 수단을 시도할지 여부, nil 이면 시도하지 않음.
+	// 요청 검증 실패와 결제 수단이 지원하지 않는 기능은 페이레터를 호출하지 않았으므로 항상 다음 결제 수단을 시도
+	ShouldFallback func(err error) bool
+}
+
+// Candidates 시도할 순서로 정렬한 결제 수단
+func (o *OneClickCheckout) Candidates(methods []EasyPayMethod) []EasyPayMethod {
+	rank := make(map[PgCode]int, len(o.MethodOrder))
+	for i, pgCode := range o.MethodOrder {
+		if _, exists := rank[pgCode]; !exists {
+			rank[pgCode] = i
+		}
+	}
+
+	candidates := make([]EasyPayMethod, 0, len(methods))
+	for _, method := range methods {
+		if method.BillKey == "" {
+			continue
+		}
+		if _, allowed := rank[method.PaymentMethod]; len(rank) > 0 && !allowed {
+			continue
+		}
+		candidates = append(candidates, method)
+	}
+
+	if len(candidates) == 0 {
+		return candidates
+	}
+
+	lastTran := func(m EasyPayMethod) time.Time {
+		t, _ := m.LastTranTime() // 형식이 잘못된 경우 결제 이력이 없는 것으로 봄
+		return t
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return lastTran(candidates[i]).After(lastTran(candidates[j]))
+	})
+
+	// 선호 결제 수단: 즐겨찾기, 없으면 최근 결제 수단
+	preferred := 0
+	for i, method := range candidates {
+		if method.FavoriteFlag == "Y" {
+			preferred = i
+			break
+		}
+	}
+
+	ordered := append(make([]EasyPayMethod, 0, len(candidates)), candidates[preferred])
+	rest := append(append(make([]EasyPayMethod, 0, len(candidates)-1), candidates[:preferred]...), candidates[preferred+1:]...)
+	if len(rank) > 0 {
+		sort.SliceStable(rest, func(i, j int) bool {
+			return rank[rest[i].PaymentMethod] < rank[rest[j].PaymentMethod]
+		})
+	}
+	return append(ordered, rest...)
+}
+
+// Charge req.UserID 의 등록한 결제 수단으로 결제, req 의 PgCode, BillKey 는 선택한 결제 수단으로 바뀜
+//
+// 할부 개월 수를 지원하지 않는 결제 수단은 결제하지 않고 건너 뜀.
+// 페이레터에 보낸 요청이 실패하면 같은 주문번호로 다시 결제하지 않도록 기본적으로 멈추고,
+// ShouldFallback 을 지정할 때는 결제되지 않은 것이 확실한 거절 코드만 true 로 처리해야 함
+func (o *OneClickCheckout) Charge(req ReqTransactionEasyPay) (res ResCheckout, err error) {
+	registered, err := o.PayLetter.GetRegisteredEasyPayMethods(ReqGetRegisteredEasyPayMethod{UserID: req.UserID})
+	if err != nil {
+		return
+	}
+
+	candidates := o.Candidates(registered.MethodList)
+	if o.MaxAttempts > 0 && len(candidates) > o.MaxAttempts {
+		candidates = candidates[:o.MaxAttempts]
+	}
+
+	for _, method := range candidates {
+		req.PgCode = method.PaymentMethod
+		req.BillKey = method.BillKey
+
+		attempt := CheckoutAttempt{BillKey: method.BillKey, PaymentMethod: method.PaymentMethod}
+		if attempt.Err = CheckInstallment(method, req); attempt.Err == nil {
+			res.Response, attempt.Err = o.PayLetter.TransactionEasyPay(req)
+		}
+		res.Attempts = append(res.Attempts, attempt)
+
+		if attempt.Err == nil {
+			res.Method = method
+			return
+		}
+		if !isLocalError(attempt.Err) && !o.shouldFallback(attempt.Err) {
+			break
+		}
+	}
+
+	err = &CheckoutError{UserID: req.UserID, Attempts: res.Attempts}
+	return
+}
+
+func (o *OneClickCheckout) shouldFallback(err error) bool {
+	if o.ShouldFallback != nil {
+		return o.ShouldFallback(err)
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package payletter
+
+import (
+	"errors"
+	"testing"
+)
+
+func newTestCheckout(methods []EasyPayMethod, charge func(req ReqTransactionEasyPay) (ResEasyPayUI, error)) (*OneClickCheckout, *fakePayLetter) {
+	p := &fakePayLetter{
+		getEasyPayMethods: func(req ReqGetRegisteredEasyPayMethod) (ResPayLetterGetEasyPayMethods, error) {
+			return ResPayLetterGetEasyPayMethods{MethodList: methods}, nil
+		},
+		transactionEasyPay: charge,
+	}
+	return &OneClickCheckout{PayLetter: p}, p
+}
+
+var checkoutMethods = []EasyPayMethod{
+	{PaymentMethod: PgCodeEasyBank, BillKey: "bank", LastTranDate: "2024-03-01 10:00:00"},
+	{PaymentMethod: PgCodeCreditCard, BillKey: "card", LastTranDate: "2024-02-01 10:00:00", InstallmentUseFlag: "Y"},
+	{PaymentMethod: PgCodeCreditCard, BillKey: "favorite", LastTranDate: "2024-01-01 10:00:00", FavoriteFlag: "Y"},
+}
+
+func TestOneClickCheckoutCandidates(t *testing.T) {
+	checkout := &OneClickCheckout{MethodOrder: []PgCode{PgCodeCreditCard, PgCodeEasyBank}}
+
+	var got []string
+	for _, method := range checkout.Candidates(checkoutMethods) {
+		got = append(got, method.BillKey)
+	}
+	if len(got) != 3 || got[0] != "favorite" || got[1] != "card" || got[2] != "bank" {
+		t.Fatalf("candidates %v", got)
+	}
+}
+
+func TestOneClickCheckoutStopsAfterRemoteFailure(t *testing.T) {
+	checkout, p := newTestCheckout(checkoutMethods, func(req ReqTransactionEasyPay) (ResEasyPayUI, error) {
+		return ResEasyPayUI{}, &PayLetterError{Code: "1001", Message: "한도 초과"}
+	})
+
+	_, err := checkout.Charge(ReqTransactionEasyPay{CommonTransactionData: CommonTransactionData{UserID: 1, OrderNo: "order-1", Amount: 1000}})
+	var checkoutErr *CheckoutError
+	if !errors.As(err, &checkoutErr) || len(checkoutErr.Attempts) != 1 {
+		t.Fatalf("err = %v", err)
+	}
+	if p.count("TransactionEasyPay") != 1 {
+		t.Fatalf("같은 주문번호로 %d 회 결제 요청", p.count("TransactionEasyPay"))
+	}
+	if code, _ := PayLetterErrorCode(err); code != "1001" {
+		t.Fatalf("code = %q", code)
+	}
+}
+
+func TestOneClickCheckoutFallsBackOnLocalErrors(t *testing.T) {
+	checkout, p := newTestCheckout(checkoutMethods, func(req ReqTransactionEasyPay) (ResEasyPayUI, error) {
+		return ResEasyPayUI{}, nil
+	})
+
+	// favorite, bank 는 할부를 지원하지 않으므로 결제 요청 없이 다음 결제 수단
+	res, err := checkout.Charge(ReqTransactionEasyPay{
+		CommonTransactionData: CommonTransactionData{UserID: 1, OrderNo: "order-1", Amount: 100000},
+		InstallMonth:          3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Method.BillKey != "card" || len(res.Attempts) != 3 || p.count("TransactionEasyPay") != 1 {
+		t.Fatalf("res %+v, calls %d", res, p.count("TransactionEasyPay"))
+	}
+	if !errors.Is(res.Attempts[0].Err, ErrValidation) {
+		t.Fatalf("첫 시도 err = %v", res.Attempts[0].Err)
+	}
+}
+
+func TestOneClickCheckoutCustomFallback(t *testing.T) {
+	checkout, p := newTestCheckout(checkoutMethods, func(req ReqTransactionEasyPay) (ResEasyPayUI, error) {
+		if req.BillKey == "favorite" {
+			return ResEasyPayUI{}, &PayLetterError{Code: "1001", Message: "한도 초과"}
+		}
+		return ResEasyPayUI{}, nil
+	})
+	checkout.ShouldFallback = func(err error) bool {
+		code, _ := PayLetterErrorCode(err)
+		return code == "1001"
+	}
+
+	res, err := checkout.Charge(ReqTransactionEasyPay{CommonTransactionData: CommonTransactionData{UserID: 1, OrderNo: "order-1", Amount: 1000}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Method.BillKey != "bank" || p.count("TransactionEasyPay") != 2 {
+		t.Fatalf("res %+v", res)
+	}
+}
@@ -0,0 +1,501 @@
+package payletter
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrPayLetterUnavailable 서킷이 열려 있어 페이레터를 호출하지 않고 바로 실패
+var ErrPayLetterUnavailable = errors.New("payletter unavailable")
+
+var errPanicked = errors.New("payletter call panicked")
+
+type UnavailableError struct {
+	Operation string
+	State     string
+	RetryAt   time.Time // 다음 probe 요청이 허용되는 시각
+}
+
+func (e *UnavailableError) Error() string {
+	return fmt.Sprintf("[%s]payletter unavailable (circuit %s, retry at %s)", e.Operation, e.State, e.RetryAt.Format(time.RFC3339))
+}
+
+func (e *UnavailableError) Unwrap() error {
+	return ErrPayLetterUnavailable
+}
+
+type CircuitBreakerConfig struct {
+	FailureRatio        float64                                 // 서킷을 여는 실패율 (0 ~ 1)
+	MinRequests         int                                     // 실패율 판단에 필요한 최소 요청 수
+	Window              time.Duration                           // closed 상태에서 실패율을 집계하는 구간
+	OpenTimeout         time.Duration                           // open 이후 half-open 으로 전환되기까지 대기 시간
+	HalfOpenMaxRequests int                                     // half-open 상태에서 허용하는 probe 요청 수
+	IsFailure           func(err error) bool                    // 실패로 집계할 에러 판별, nil 이면 isServerFailure (결제 거절 등 업무 에러는 집계하지 않음)
+	OnStateChange       func(operation string, from, to string) // 상태 전환 시 호출 (metrics, 로그 용)
+}
+
+var DefaultCircuitBreakerConfig = CircuitBreakerConfig{
+	FailureRatio:        0.5,
+	MinRequests:         10,
+	Window:              time.Minute,
+	OpenTimeout:         30 * time.Second,
+	HalfOpenMaxRequests: 1,
+}
+
+func (o CircuitBreakerConfig) withDefaults() CircuitBreakerConfig {
+	if o.FailureRatio <= 0 || o.FailureRatio > 1 {
+		o.FailureRatio = DefaultCircuitBreakerConfig.FailureRatio
+	}
+	if o.MinRequests <= 0 {
+		o.MinRequests = DefaultCircuitBreakerConfig.MinRequests
+	}
+	if o.Window <= 0 {
+		o.Window = DefaultCircuitBreakerConfig.Window
+	}
+	if o.OpenTimeout <= 0 {
+		o.OpenTimeout = DefaultCircuitBreakerConfig.OpenTimeout
+	}
+	if o.HalfOpenMaxRequests <= 0 {
+		o.HalfOpenMaxRequests = DefaultCircuitBreakerConfig.HalfOpenMaxRequests
+	}
+	if o.IsFailure == nil {
+		o.IsFailure = isServerFailure
+	}
+	return o
+}
+
+// isServerFailure 페이레터 장애로 볼 수 있는 에러, 500 응답과 transport 에러, panic
+//
+// 요청 검증 실패와 결제 거절, 잔액 부족 같은 업무 에러 응답은 페이레터가 정상 동작한 것이므로 제외
+func isServerFailure(err error) bool {
+	if err == nil || isLocalError(err) || errors.Is(err, ErrTransactionNotFound) {
+		return false
+	}
+	var e *PayLetterError
+	if errors.As(err, &e) {
+		return e.Server
+	}
+	return true
+}
+
+type CircuitBreakerStatus struct {
+	Operation string    `json:"operation"`
+	State     string    `json:"state"`
+	Requests  int       `json:"requests"` // 현재 집계 구간의 요청 수
+	Failures  int       `json:"failures"` // 현재 집계 구간의 실패 수
+	OpenedAt  time.Time `json:"opened_at"`
+}
+
+type stateChange struct {
+	from string
+	to   string
+}
+
+type circuitBreaker struct {
+	mu          sync.Mutex
+	operation   string
+	config      CircuitBreakerConfig
+	state       string
+	generation  uint64 // 상태가 바뀔 때마다 증가, 이전 상태에서 시작된 요청의 결과는 무시
+	requests    int
+	failures    int
+	inFlight    int // half-open 상태에서 진행 중인 probe 수
+	successes   int // half-open 상태에서 성공한 probe 수
+	windowStart time.Time
+	openedAt    time.Time
+	changes     []stateChange
+}
+
+func newCircuitBreaker(operation string, config CircuitBreakerConfig) *circuitBreaker {
+	return &circuitBreaker{
+		operation:   operation,
+		config:      config,
+		state:       CircuitState.Closed,
+		windowStart: time.Now(),
+	}
+}
+
+func (o *circuitBreaker) allow(now time.Time) (generation uint64, err error) {
+	o.mu.Lock()
+	defer o.unlock()
+
+	switch o.state {
+	case CircuitState.Open:
+		retryAt := o.openedAt.Add(o.config.OpenTimeout)
+		if now.Before(retryAt) {
+			err = &UnavailableError{Operation: o.operation, State: o.state, RetryAt: retryAt}
+			return
+		}
+		o.setState(CircuitState.HalfOpen, now)
+		fallthrough
+	case CircuitState.HalfOpen:
+		if o.inFlight+o.successes >= o.config.HalfOpenMaxRequests {
+			err = &UnavailableError{Operation: o.operation, State: o.state, RetryAt: now.Add(o.config.OpenTimeout)}
+			return
+		}
+		o.inFlight++
+	default:
+		if now.Sub(o.windowStart) >= o.config.Window {
+			o.requests, o.failures = 0, 0
+			o.windowStart = now
+		}
+	}
+
+	generation = o.generation
+	return
+}
+
+func (o *circuitBreaker) done(generation uint64, err error, now time.Time) {
+	o.mu.Lock()
+	defer o.unlock()
+
+	if generation != o.generation {
+		return
+	}
+
+	// 요청 검증 실패는 페이레터를 호출하지 않았으므로 probe, 실패율 집계에서 제외
+	if isLocalError(err) {
+		if o.state == CircuitState.HalfOpen {
+			o.inFlight--
+		}
+		return
+	}
+
+	failed := err != nil && o.config.IsFailure(err)
+	switch o.state {
+	case CircuitState.HalfOpen:
+		o.inFlight--
+		if failed {
+			o.setState(CircuitState.Open, now)
+			return
+		}
+		o.successes++
+		if o.successes >= o.config.HalfOpenMaxRequests {
+			o.setState(CircuitState.Closed, now)
+		}
+	case CircuitState.Closed:
+		o.requests++
+		if failed {
+			o.failures++
+		}
+		if o.requests >= o.config.MinRequests && float64(o.failures)/float64(o.requests) >= o.config.FailureRatio {
+			o.setState(CircuitState.Open, now)
+		}
+	}
+}
+
+func (o *circuitBreaker) setState(state string, now time.Time) {
+	o.changes = append(o.changes, stateChange{from: o.state, to: state})
+	o.state = state
+	o.generation++
+	o.requests, o.failures, o.inFlight, o.successes = 0, 0, 0, 0
+	o.windowStart = now
+	if state == CircuitState.Open {
+		o.openedAt = now
+	}
+}
+
+// unlock 상태 전환 콜백은 lock 을 풀고 호출
+func (o *circuitBreaker) unlock() {
+	changes := o.changes
+	o.changes = nil
+	o.mu.Unlock()
+
+	if o.config.OnStateChange == nil {
+		return
+	}
+	for _, c := range changes {
+		o.config.OnStateChange(o.operation, c.from, c.to)
+	}
+}
+
+func (o *circuitBreaker) status() CircuitBreakerStatus {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return CircuitBreakerStatus{
+		Operation: o.operation,
+		State:     o.state,
+		Requests:  o.requests,
+		Failures:  o.failures,
+		OpenedAt:  o.openedAt,
+	}
+}
+
+// CircuitBreakerPayLetter 엔드포인트(operation) 별 서킷 브레이커를 적용한 pay letter
+type CircuitBreakerPayLetter struct {
+	payLetter IPayLetter
+	config    CircuitBreakerConfig
+
+	mu       sync.Mutex
+	breakers map[string]*circuitBreaker
+}
+
+var _ IPayLetter = (*CircuitBreakerPayLetter)(nil)
+
+func GetCircuitBreakerPayLetter(p IPayLetter, config CircuitBreakerConfig) *CircuitBreakerPayLetter {
+	return &CircuitBreakerPayLetter{
+		payLetter: p,
+		config:    config.withDefaults(),
+		breakers:  make(map[string]*circuitBreaker),
+	}
+}
+
+func (o *CircuitBreakerPayLetter) breaker(operation string) *circuitBreaker {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	cb, exists := o.breakers[operation]
+	if !exists {
+		cb = newCircuitBreaker(operation, o.config)
+		o.breakers[operation] = cb
+	}
+	return cb
+}
+
+func (o *CircuitBreakerPayLetter) call(operation string, fn func() error) (err error) {
+	cb := o.breaker(operation)
+	generation, err := cb.allow(time.Now())
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil { // 응답 파싱 중 panic 도 실패로 집계
+			cb.done(generation, errPanicked, time.Now())
+			panic(r)
+		}
+	}()
+
+	err = fn()
+	cb.done(generation, err, time.Now())
+	return
+}
+
+// State operation 의 현재 서킷 상태
+func (o *CircuitBreakerPayLetter) State(operation string) string {
+	return o.breaker(operation).status().State
+}
+
+// Status 호출된 적 있는 모든 operation 의 서킷 상태 (health check, metrics 용)
+func (o *CircuitBreakerPayLetter) Status() []CircuitBreakerStatus {
+	o.mu.Lock()
+	breakers := make([]*circuitBreaker, 0, len(o.breakers))
+	for _, cb := range o.breakers {
+		breakers = append(breakers, cb)
+	}
+	o.mu.Unlock()
+
+	statuses := make([]CircuitBreakerStatus, 0, len(breakers))
+	for _, cb := range breakers {
+		statuses = append(statuses, cb.status())
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Operation < statuses[j].Operation })
+
+	return statuses
+}
+
+// Healthy 열린 서킷이 하나도 없으면 true
+func (o *CircuitBreakerPayLetter) Healthy() bool {
+	for _, s := range o.Status() {
+		if s.State == CircuitState.Open {
+			return false
+		}
+	}
+	return true
+}
+
+func (o *CircuitBreakerPayLetter) RegisterAutoPay(req ReqRegisterAutoPay) (res ResRegisterAutoPay, err error) {
+	err = o.call(Operation.RegisterAutoPay, func() (err error) {
+		res, err = o.payLetter.RegisterAutoPay(req)
+		return
+	})
+	return
+}
+
+func (o *CircuitBreakerPayLetter) TransactionAutoPay(req ReqTransactionAutoPay) (res ResTransactionAutoPay, err error) {
+	err = o.call(Operation.TransactionAutoPay, func() (err error) {
+		res, err = o.payLetter.TransactionAutoPay(req)
+		return
+	})
+	return
+}
+
+func (o *CircuitBreakerPayLetter) CancelTransaction(req ReqCancelTransaction) (res ResCancelTransaction, err error) {
+	err = o.call(Operation.CancelTransaction, func() (err error) {
+		res, err = o.payLetter.CancelTransaction(req)
+		return
+	})
+	return
+}
+
+func (o *CircuitBreakerPayLetter) PartialCancelTransaction(req ReqPartialCancelTransaction) (res ResPartialCancelTransaction, err error) {
+	err = o.call(Operation.PartialCancelTransaction, func() (err error) {
+		res, err = o.payLetter.PartialCancelTransaction(req)
+		return
+	})
+	return
+}
+
+func (o *CircuitBreakerPayLetter) RegisterEasyPay(req ReqRegisterEasyPay) (res ResEasyPayUI, err error) {
+	err = o.call(Operation.RegisterEasyPay, func() (err error) {
+		res, err = o.payLetter.RegisterEasyPay(req)
+		return
+	})
+	return
+}
+
+func (o *CircuitBreakerPayLetter) GetRegisteredEasyPayMethods(req ReqGetRegisteredEasyPayMethod) (res ResPayLetterGetEasyPayMethods, err error) {
+	err = o.call(Operation.GetRegisteredEasyPayMethods, func() (err error) {
+		res, err = o.payLetter.GetRegisteredEasyPayMethods(req)
+		return
+	})
+	return
+}
+
+func (o *CircuitBreakerPayLetter) CancelEasyPay(req ReqCancelEasyPay) (res ResCancelEasyPay, err error) {
+	err = o.call(Operation.CancelEasyPay, func() (err error) {
+		res, err = o.payLetter.CancelEasyPay(req)
+		return
+	})
+	return
+}
+
+func (o *CircuitBreakerPayLetter) TransactionEasyPay(req ReqTransactionEasyPay) (res ResEasyPayUI, err error) {
+	err = o.call(Operation.TransactionEasyPay, func() (err error) {
+		res, err = o.payLetter.TransactionEasyPay(req)
+		return
+	})
+	return
+}
+
+func (o *CircuitBreakerPayLetter) TransactionNormalPay(req ReqTransactionNormalPay) (res ResTransactionNormalPay, err error) {
+	err = o.call(Operation.TransactionNormalPay, func() (err error) {
+		res, err = o.payLetter.TransactionNormalPay(req)
+		return
+	})
+	return
+}
+
+func (o *CircuitBreakerPayLetter) GetTransactionList(req ReqGetTransactionList) (res ResGetTransactionList, err error) {
+	err = o.call(Operation.GetTransactionList, func() (err error) {
+		res, err = o.payLetter.GetTransactionList(req)
+		return
+	})
+	return
+}
+
+func (o *CircuitBreakerPayLetter) IssueVirtualAccount(req ReqIssueVirtualAccount) (res ResTransactionNormalPay, err error) {
+	err = o.call(Operation.IssueVirtualAccount, func() (err error) {
+		res, err = o.payLetter.IssueVirtualAccount(req)
+		return
+	})
+	return
+}
+
+func (o *CircuitBreakerPayLetter) RefundVirtualAccount(req ReqRefundVirtualAccount) (res ResCancelTransaction, err error) {
+	err = o.call(Operation.RefundVirtualAccount, func() (err error) {
+		res, err = o.payLetter.RefundVirtualAccount(req)
+		return
+	})
+	return
+}
+
+func (o *CircuitBreakerPayLetter) IssueCashReceipt(req ReqIssueCashReceipt) (res ResCashReceipt, err error) {
+	err = o.call(Operation.IssueCashReceipt, func() (err error) {
+		res, err = o.payLetter.IssueCashReceipt(req)
+		return
+	})
+	return
+}
+
+func (o *CircuitBreakerPayLetter) ReissueCashReceipt(req ReqIssueCashReceipt) (res ResCashReceipt, err error) {
+	err = o.call(Operation.ReissueCashReceipt, func() (err error) {
+		res, err = o.payLetter.ReissueCashReceipt(req)
+		return
+	})
+	return
+}
+
+func (o *CircuitBreakerPayLetter) CancelCashReceipt(req ReqCancelCashReceipt) (res ResCashReceipt, err error) {
+	err = o.call(Operation.CancelCashReceipt, func() (err error) {
+		res, err = o.payLetter.CancelCashReceipt(req)
+		return
+	})
+	return
+}
+
+func (o *CircuitBreakerPayLetter) GetCashReceipt(req ReqGetCashReceipt) (res ResCashReceipt, err error) {
+	err = o.call(Operation.GetCashReceipt, func() (err error) {
+		res, err = o.payLetter.GetCashReceipt(req)
+		return
+	})
+	return
+}
+
+func (o *CircuitBreakerPayLetter) GetTransaction(req ReqGetTransaction) (res ResGetTransaction, err error) {
+	err = o.call(Operation.GetTransaction, func() (err error) {
+		res, err = o.payLetter.GetTransaction(req)
+		return
+	})
+	return
+}
+
+func (o *CircuitBreakerPayLetter) DeleteEasyPayMethod(req ReqDeleteEasyPayMethod) (res ResUpdateEasyPayMethod, err error) {
+	err = o.call(Operation.DeleteEasyPayMethod, func() (err error) {
+		res, err = o.payLetter.DeleteEasyPayMethod(req)
+		return
+	})
+	return
+}
+
+func (o *CircuitBreakerPayLetter) RenameEasyPayMethod(req ReqRenameEasyPayMethod) (res ResUpdateEasyPayMethod, err error) {
+	err = o.call(Operation.RenameEasyPayMethod, func() (err error) {
+		res, err = o.payLetter.RenameEasyPayMethod(req)
+		return
+	})
+	return
+}
+
+func (o *CircuitBreakerPayLetter) SetFavoriteEasyPayMethod(req ReqSetFavoriteEasyPayMethod) (res ResUpdateEasyPayMethod, err error) {
+	err = o.call(Operation.SetFavoriteEasyPayMethod, func() (err error) {
+		res, err = o.payLetter.SetFavoriteEasyPayMethod(req)
+		return
+	})
+	return
+}
+
+func (o *CircuitBreakerPayLetter) UnregisterEasyPay(req ReqUnregisterEasyPay) (res ResUpdateEasyPayMethod, err error) {
+	err = o.call(Operation.UnregisterEasyPay, func() (err error) {
+		res, err = o.payLetter.UnregisterEasyPay(req)
+		return
+	})
+	return
+}
+
+func (o *CircuitBreakerPayLetter) ResetEasyPayPassword(req ReqEasyPayPassword) (res ResEasyPayUI, err error) {
+	err = o.call(Operation.ResetEasyPayPassword, func() (err error) {
+		res, err = o.payLetter.ResetEasyPayPassword(req)
+		return
+	})
+	return
+}
+
+func (o *CircuitBreakerPayLetter) ChangeEasyPayPassword(req ReqEasyPayPassword) (res ResEasyPayUI, err error) {
+	err = o.call(Operation.ChangeEasyPayPassword, func() (err error) {
+		res, err = o.payLetter.ChangeEasyPayPassword(req)
+		return
+	})
+	return
+}
+
+func (o *CircuitBreakerPayLetter) SetEasyPayPasswordSkip(req ReqSetEasyPayPasswordSkip) (res ResEasyPayUI, err error) {
+	err = o.call(Operation.SetEasyPayPasswordSkip, func() (err error) {
+		res, err = o.payLetter.SetEasyPayPasswordSkip(req)
+		return
+	})
+	return
+}
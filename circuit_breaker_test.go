@@ -0,0 +1,149 @@
+package payletter
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestBreaker(p IPayLetter) *CircuitBreakerPayLetter {
+	return GetCircuitBreakerPayLetter(p, CircuitBreakerConfig{
+		FailureRatio: 0.5,
+		MinRequests:  4,
+		Window:       time.Minute,
+		OpenTimeout:  20 * time.Millisecond,
+	})
+}
+
+func TestCircuitBreakerIgnoresDeclines(t *testing.T) {
+	p := &fakePayLetter{
+		transactionAutoPay: func(req ReqTransactionAutoPay) (ResTransactionAutoPay, error) {
+			return ResTransactionAutoPay{}, &PayLetterError{Code: "1001", Message: "한도 초과"}
+		},
+	}
+	cb := newTestBreaker(p)
+
+	for i := 0; i < 10; i++ {
+		_, err := cb.TransactionAutoPay(ReqTransactionAutoPay{})
+		if code, _ := PayLetterErrorCode(err); code != "1001" {
+			t.Fatalf("err = %v", err)
+		}
+	}
+	if state := cb.State(Operation.TransactionAutoPay); state != CircuitState.Closed {
+		t.Fatalf("결제 거절로 서킷 상태 %s", state)
+	}
+	if p.count("TransactionAutoPay") != 10 {
+		t.Fatalf("TransactionAutoPay %d 회 호출", p.count("TransactionAutoPay"))
+	}
+}
+
+func TestCircuitBreakerOpensOnServerErrors(t *testing.T) {
+	fail := true
+	p := &fakePayLetter{
+		transactionAutoPay: func(req ReqTransactionAutoPay) (ResTransactionAutoPay, error) {
+			if fail {
+				return ResTransactionAutoPay{}, &PayLetterError{Code: "500", Message: "internal error", Server: true}
+			}
+			return ResTransactionAutoPay{TID: "tid"}, nil
+		},
+	}
+	cb := newTestBreaker(p)
+
+	for i := 0; i < 4; i++ {
+		_, _ = cb.TransactionAutoPay(ReqTransactionAutoPay{})
+	}
+	if state := cb.State(Operation.TransactionAutoPay); state != CircuitState.Open {
+		t.Fatalf("서킷 상태 %s, want open", state)
+	}
+
+	_, err := cb.TransactionAutoPay(ReqTransactionAutoPay{})
+	if !errors.Is(err, ErrPayLetterUnavailable) {
+		t.Fatalf("err = %v, want ErrPayLetterUnavailable", err)
+	}
+	if p.count("TransactionAutoPay") != 4 {
+		t.Fatalf("서킷이 열린 뒤 페이레터 호출, %d 회", p.count("TransactionAutoPay"))
+	}
+
+	// open timeout 이후 probe 성공으로 닫힘
+	time.Sleep(30 * time.Millisecond)
+	fail = false
+	if _, err = cb.TransactionAutoPay(ReqTransactionAutoPay{}); err != nil {
+		t.Fatal(err)
+	}
+	if state := cb.State(Operation.TransactionAutoPay); state != CircuitState.Closed {
+		t.Fatalf("probe 성공 후 서킷 상태 %s", state)
+	}
+}
+
+func TestCircuitBreakerHalfOpenIgnoresLocalErrors(t *testing.T) {
+	var next error = &PayLetterError{Code: "500", Message: "internal error", Server: true}
+	p := &fakePayLetter{
+		transactionAutoPay: func(req ReqTransactionAutoPay) (ResTransactionAutoPay, error) {
+			return ResTransactionAutoPay{}, next
+		},
+	}
+	cb := newTestBreaker(p)
+
+	for i := 0; i < 4; i++ {
+		_, _ = cb.TransactionAutoPay(ReqTransactionAutoPay{})
+	}
+	time.Sleep(30 * time.Millisecond) // OpenTimeout
+
+	// 요청 검증 실패는 probe 로 보지 않으므로 서킷이 닫히지 않고 다음 요청이 probe 가 됨
+	next = &ValidationError{}
+	if _, err := cb.TransactionAutoPay(ReqTransactionAutoPay{}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("err = %v", err)
+	}
+	if state := cb.State(Operation.TransactionAutoPay); state != CircuitState.HalfOpen {
+		t.Fatalf("요청 검증 실패 후 서킷 상태 %s, want half-open", state)
+	}
+
+	next = &PayLetterError{Code: "500", Message: "internal error", Server: true}
+	_, _ = cb.TransactionAutoPay(ReqTransactionAutoPay{})
+	if state := cb.State(Operation.TransactionAutoPay); state != CircuitState.Open {
+		t.Fatalf("probe 실패 후 서킷 상태 %s, want open", state)
+	}
+}
+
+func TestCircuitBreakerCountsPanics(t *testing.T) {
+	p := &fakePayLetter{
+		transactionAutoPay: func(req ReqTransactionAutoPay) (ResTransactionAutoPay, error) {
+			panic("unexpected response")
+		},
+	}
+	cb := newTestBreaker(p)
+
+	for i := 0; i < 4; i++ {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("panic 이 전달되지 않음")
+				}
+			}()
+			_, _ = cb.TransactionAutoPay(ReqTransactionAutoPay{})
+		}()
+	}
+	if state := cb.State(Operation.TransactionAutoPay); state != CircuitState.Open {
+		t.Fatalf("서킷 상태 %s, want open", state)
+	}
+}
+
+func TestIsServerFailure(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{&ValidationError{}, false},
+		{ErrUnsupportedOperation, false},
+		{ErrTransactionNotFound, false},
+		{&PayLetterError{Code: "1001"}, false},
+		{&PayLetterError{Code: "500", Server: true}, true},
+		{errors.New("connection reset"), true},
+	}
+	for _, tt := range tests {
+		if got := isServerFailure(tt.err); got != tt.want {
+			t.Errorf("isServerFailure(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -5,23 +5,6 @@ import (
 	"strings"
 )
 
-type pgCode struct {
-	CreditCard string
-	EasyBank   string
-	NaverPay   string
-	NaverCard  string
-	NaverPoint string
-}
-
-func (o *pgCode) IsNaverCode(code string) bool {
-	switch code {
-	case o.NaverPoint, o.NaverCard, o.NaverPay:
-		return true
-	default:
-		return false
-	}
-}
-
 type payletterCardCode struct {
 	P001     string `value:"비씨카드"`
 	P002     string `value:"KB국민카드"`
@@ -56,6 +39,85 @@ type transactionDateType struct {
 	Settle      string
 }
 
+type operation struct {
+	RegisterAutoPay             string
+	TransactionAutoPay          string
+	CancelTransaction           string
+	PartialCancelTransaction    string
+	RegisterEasyPay             string
+	GetRegisteredEasyPayMethods string
+	CancelEasyPay               string
+	TransactionEasyPay          string
+	TransactionNormalPay        string
+	GetTransactionList          string
+	IssueVirtualAccount         string
+	RefundVirtualAccount        string
+	IssueCashReceipt            string
+	ReissueCashReceipt          string
+	CancelCashReceipt           string
+	GetCashReceipt              string
+	GetTransaction              string
+	DeleteEasyPayMethod         string
+	RenameEasyPayMethod         string
+	SetFavoriteEasyPayMethod    string
+	UnregisterEasyPay           string
+	ResetEasyPayPassword        string
+	ChangeEasyPayPassword       string
+	SetEasyPayPasswordSkip      string
+}
+
+type paymentState struct {
+	Requested          string // 결제창 URL 발급
+	Authorized         string // 결제 승인
+	PartiallyCancelled string // 부분 취소
+	Cancelled          string // 전체 취소
+	Failed             string // 결제 실패
+}
+
+type registrationState struct {
+	Pending   string // 결제창 URL 발급, callback 대기
+	Completed string // billkey 저장
+	Failed    string // 등록 실패, 취소
+}
+
+type batchItemStatus struct {
+	InProgress string // 결제 요청 중, 중단된 경우 재개 시 결제 여부를 조회 후 처리
+	Succeeded  string
+	Declined   string // 페이레터 결제 거절, 재개 시 다시 결제하지 않음
+	Failed     string // 결제되지 않은 것이 확실한 실패 (요청 검증 실패, 페이레터 에러 응답), 재개 시 다시 결제
+	Unknown    string // 결제 여부를 알 수 없는 실패 (페이레터 장애, 통신 에러, panic), 재개 시 결제 여부를 조회 후 처리
+}
+
+type sweepOutcome struct {
+	Abandoned        string // 결제되지 않은 주문, 저장소에서 삭제
+	Cancelled        string // 결제되었지만 처리되지 않은 주문, 결제 취소
+	AlreadyCancelled string // 이미 전체 취소된 주문
+	Failed           string // 조회, 취소 실패, 다음 sweep 에서 재시도
+}
+
+type callbackRejectReason struct {
+	Method    string // POST 가 아님
+	IP        string // 허용하지 않은 IP
+	Size      string // 요청 크기 초과
+	Malformed string // 요청 형식 오류
+	Hash      string // payhash 검증 실패
+	Replay    string // 처리된 TID 에 다른 결제 정보
+}
+
+type paymentEventType struct {
+	PaymentApproved         string
+	PaymentCancelled        string
+	PartialRefunded         string
+	BillKeyIssued           string
+	EasyPayMethodRegistered string
+}
+
+type circuitState struct {
+	Closed   string
+	Open     string
+	HalfOpen string
+}
+
 const (
 	registerAutoPayUrl                = "https://pgapi.payletter.com/v1.0/payments/request"
 	transactionAutoPayUrl             = "https://pgapi.payletter.com/v1.0/payments/autopay"
@@ -69,12 +131,29 @@ const (
 	easyPayCancelTestUrl              = "https://testppay.payletter.com/api/payments/cancel"
 	easyPayTransactionUrl             = "https://ppay.payletter.com/api/url/request/request-payment"
 	easyPayTransactionTestUrl         = "https://testppay.payletter.com/api/url/request/request-payment"
+	easyPayDeleteMethodUrl            = "https://ppay.payletter.com/api/user/methods/delete"
+	easyPayDeleteMethodTestUrl        = "https://testppay.payletter.com/api/user/methods/delete"
+	easyPayRenameMethodUrl            = "https://ppay.payletter.com/api/user/methods/alias"
+	easyPayRenameMethodTestUrl        = "https://testppay.payletter.com/api/user/methods/alias"
+	easyPayFavoriteMethodUrl          = "https://ppay.payletter.com/api/user/methods/favorite"
+	easyPayFavoriteMethodTestUrl      = "https://testppay.payletter.com/api/user/methods/favorite"
+	easyPayUnregisterUrl              = "https://ppay.payletter.com/api/user/withdraw"
+	easyPayUnregisterTestUrl          = "https://testppay.payletter.com/api/user/withdraw"
+	easyPayResetPasswordUrl           = "https://ppay.payletter.com/api/url/request/reset-password"
+	easyPayResetPasswordTestUrl       = "https://testppay.payletter.com/api/url/request/reset-password"
+	easyPayChangePasswordUrl          = "https://ppay.payletter.com/api/url/request/change-password"
+	easyPayChangePasswordTestUrl      = "https://testppay.payletter.com/api/url/request/change-password"
+	easyPayPasswordSkipUrl            = "https://ppay.payletter.com/api/url/request/password-skip"
+	easyPayPasswordSkipTestUrl        = "https://testppay.payletter.com/api/url/request/password-skip"
 	normalTransactionUrl              = "https://pgapi.payletter.com/v1.0/payments/request"
 	getTransactionListUrl             = "https://pgapi.payletter.com/v1.0/payments/transaction/list"
+	getTransactionUrl                 = "https://pgapi.payletter.com/v1.0/payments/transaction"
+	cashReceiptIssueUrl               = "https://pgapi.payletter.com/v1.0/receipts/cash"
+	cashReceiptCancelUrl              = "https://pgapi.payletter.com/v1.0/receipts/cash/cancel"
+	cashReceiptStatusUrl              = "https://pgapi.payletter.com/v1.0/receipts/cash/status"
 )
 
 var (
-	PgCode   = utils.NewStringEnum[pgCode](nil, strings.ToLower)
 	CardCode = utils.NewConstantFromTag[payletterCardCode](strings.ToUpper)
 	BankCode = map[string]string{
 		"003": "IBK기업은행",
@@ -102,4 +181,12 @@ var (
 		"071": "우체국은행",
 	}
 	TransactionDateType = utils.NewStringEnum[transactionDateType](nil, strings.ToLower)
+	Operation           = utils.NewStringEnum[operation](nil, strings.ToLower)
+	CircuitState        = utils.NewStringEnum[circuitState](nil, strings.ToLower)
+	PaymentState        = utils.NewStringEnum[paymentState](nil, strings.ToLower)
+	RegistrationState   = utils.NewStringEnum[registrationState](nil, strings.ToLower)
+	BatchItemStatus     = utils.NewStringEnum[batchItemStatus](nil, strings.ToLower)
+	SweepOutcome        = utils.NewStringEnum[sweepOutcome](nil, strings.ToLower)
+	CallbackReject      = utils.NewStringEnum[callbackRejectReason](nil, strings.ToLower)
+	PaymentEventType    = utils.NewStringEnum[paymentEventType](nil, strings.ToLower)
 )
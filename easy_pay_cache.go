@@ -0,0 +1,263 @@
+package payletter
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+const defaultEasyPayMethodCacheTTL = time.Minute
+
+type IEasyPayMethodCache interface {
+	// Get 만료되지 않은 목록이 있으면 found = true
+	Get(userID int) (res ResPayLetterGetEasyPayMethods, found bool, err error)
+	Set(userID int, res ResPayLetterGetEasyPayMethods, ttl time.Duration) error
+	Delete(userID int) error
+}
+
+// CachedEasyPayMethodPayLetter 등록한 간편결제 수단 목록 조회 결과를 캐시
+//
+// 결제 수단 등록, 삭제, 변경, 간편결제 결제가 성공하면 해당 사용자의 캐시를 지움.
+// 결제 수단 등록은 callback 으로 완료 되므로 등록 callback 처리 후 Invalidate 를 호출해야 함.
+// 조회 중에 같은 사용자의 Invalidate 가 호출되면 조회 결과는 캐시하지 않음 (같은 프로세스 안에서만 보장)
+type CachedEasyPayMethodPayLetter struct {
+	IPayLetter
+	Cache   IEasyPayMethodCache
+	TTL     time.Duration               // 0 이면 1분
+	OnError func(userID int, err error) // 캐시 backend 에러 시 호출 (로그, metrics 용), 조회는 페이레터로 진행
+
+	mu      sync.Mutex
+	fetches map[int]*easyPayMethodFetches // 페이레터 조회 중인 사용자
+}
+
+// easyPayMethodFetches 한 사용자의 진행 중인 조회, generation 은 Invalidate 마다 증가
+type easyPayMethodFetches struct {
+	count      int
+	generation uint64
+}
+
+func GetCachedEasyPayMethodPayLetter(p IPayLetter, cache IEasyPayMethodCache) *CachedEasyPayMethodPayLetter {
+	return &CachedEasyPayMethodPayLetter{
+		IPayLetter: p,
+		Cache:      cache,
+	}
+}
+
+func (o *CachedEasyPayMethodPayLetter) GetRegisteredEasyPayMethods(req ReqGetRegisteredEasyPayMethod) (res ResPayLetterGetEasyPayMethods, err error) {
+	cached, found, err := o.Cache.Get(req.UserID)
+	if err != nil {
+		o.onError(req.UserID, err)
+	} else if found {
+		return cloneEasyPayMethods(cached), nil
+	}
+
+	generation := o.beginFetch(req.UserID)
+	res, err = o.IPayLetter.GetRegisteredEasyPayMethods(req)
+	o.endFetch(req.UserID, generation, func() {
+		if err != nil {
+			return
+		}
+		ttl := o.TTL
+		if ttl <= 0 {
+			ttl = defaultEasyPayMethodCacheTTL
+		}
+		if setErr := o.Cache.Set(req.UserID, cloneEasyPayMethods(res), ttl); setErr != nil {
+			o.onError(req.UserID, setErr)
+		}
+	})
+	return
+}
+
+// Invalidate userID 의 캐시 삭제, 진행 중인 조회의 결과도 캐시하지 않음
+func (o *CachedEasyPayMethodPayLetter) Invalidate(userID int) {
+	o.mu.Lock()
+	if f, exists := o.fetches[userID]; exists {
+		f.generation++
+	}
+	o.mu.Unlock()
+
+	if err := o.Cache.Delete(userID); err != nil {
+		o.onError(userID, err)
+	}
+}
+
+func (o *CachedEasyPayMethodPayLetter) beginFetch(userID int) (generation uint64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.fetches == nil {
+		o.fetches = make(map[int]*easyPayMethodFetches)
+	}
+	f, exists := o.fetches[userID]
+	if !exists {
+		f = &easyPayMethodFetches{}
+		o.fetches[userID] = f
+	}
+	f.count++
+	return f.generation
+}
+
+// endFetch 조회 시작 후 Invalidate 가 없었을 때만 set 호출,
+// set 을 lock 안에서 호출하므로 이후의 Invalidate 는 set 이 끝난 뒤에 캐시를 지움
+func (o *CachedEasyPayMethodPayLetter) endFetch(userID int, generation uint64, set func()) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	f := o.fetches[userID]
+	if f.generation == generation {
+		set()
+	}
+	if f.count--; f.count == 0 {
+		delete(o.fetches, userID)
+	}
+}
+
+func (o *CachedEasyPayMethodPayLetter) RegisterEasyPay(req ReqRegisterEasyPay) (res ResEasyPayUI, err error) {
+	if res, err = o.IPayLetter.RegisterEasyPay(req); err == nil {
+		o.Invalidate(req.UserID)
+	}
+	return
+}
+
+func (o *CachedEasyPayMethodPayLetter) TransactionEasyPay(req ReqTransactionEasyPay) (res ResEasyPayUI, err error) {
+	if res, err = o.IPayLetter.TransactionEasyPay(req); err == nil {
+		o.Invalidate(req.UserID) // 최근 결제일 변경
+	}
+	return
+}
+
+func (o *CachedEasyPayMethodPayLetter) DeleteEasyPayMethod(req ReqDeleteEasyPayMethod) (res ResUpdateEasyPayMethod, err error) {
+	if res, err = o.IPayLetter.DeleteEasyPayMethod(req); err == nil {
+		o.Invalidate(req.UserID)
+	}
+	return
+}
+
+func (o *CachedEasyPayMethodPayLetter) RenameEasyPayMethod(req ReqRenameEasyPayMethod) (res ResUpdateEasyPayMethod, err error) {
+	if res, err = o.IPayLetter.RenameEasyPayMethod(req); err == nil {
+		o.Invalidate(req.UserID)
+	}
+	return
+}
+
+func (o *CachedEasyPayMethodPayLetter) SetFavoriteEasyPayMethod(req ReqSetFavoriteEasyPayMethod) (res ResUpdateEasyPayMethod, err error) {
+	if res, err = o.IPayLetter.SetFavoriteEasyPayMethod(req); err == nil {
+		o.Invalidate(req.UserID)
+	}
+	return
+}
+
+func (o *CachedEasyPayMethodPayLetter) UnregisterEasyPay(req ReqUnregisterEasyPay) (res ResUpdateEasyPayMethod, err error) {
+	if res, err = o.IPayLetter.UnregisterEasyPay(req); err == nil {
+		o.Invalidate(req.UserID)
+	}
+	return
+}
+
+func (o *CachedEasyPayMethodPayLetter) SetEasyPayPasswordSkip(req ReqSetEasyPayPasswordSkip) (res ResEasyPayUI, err error) {
+	if res, err = o.IPayLetter.SetEasyPayPasswordSkip(req); err == nil {
+		o.Invalidate(req.UserID)
+	}
+	return
+}
+
+func (o *CachedEasyPayMethodPayLetter) onError(userID int, err error) {
+	if o.OnError != nil {
+		o.OnError(userID, err)
+	}
+}
+
+func cloneEasyPayMethods(res ResPayLetterGetEasyPayMethods) ResPayLetterGetEasyPayMethods {
+	res.MethodCount = append([]EasyPayMethodCount(nil), res.MethodCount...)
+	res.MethodList = append([]EasyPayMethod(nil), res.MethodList...)
+	return res
+}
+
+type easyPayMethodCacheEntry struct {
+	userID    int
+	res       ResPayLetterGetEasyPayMethods
+	expiresAt time.Time
+}
+
+// MemoryEasyPayMethodCache 최대 capacity 명까지 저장하는 LRU 캐시
+type MemoryEasyPayMethodCache struct {
+	capacity int
+	now      Clock
+
+	mu      sync.Mutex
+	entries map[int]*list.Element
+	order   *list.List // 앞쪽이 최근 사용
+}
+
+func NewMemoryEasyPayMethodCache(capacity int, opts ...Option) *MemoryEasyPayMethodCache {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &MemoryEasyPayMethodCache{
+		capacity: capacity,
+		now:      newOptions(opts).clock,
+		entries:  make(map[int]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (o *MemoryEasyPayMethodCache) Get(userID int) (res ResPayLetterGetEasyPayMethods, found bool, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	e, exists := o.entries[userID]
+	if !exists {
+		return
+	}
+	entry := e.Value.(*easyPayMethodCacheEntry)
+	if !o.now().Before(entry.expiresAt) {
+		o.remove(e)
+		return
+	}
+
+	o.order.MoveToFront(e)
+	return entry.res, true, nil
+}
+
+func (o *MemoryEasyPayMethodCache) Set(userID int, res ResPayLetterGetEasyPayMethods, ttl time.Duration) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	entry := &easyPayMethodCacheEntry{
+		userID:    userID,
+		res:       res,
+		expiresAt: o.now().Add(ttl),
+	}
+	if e, exists := o.entries[userID]; exists {
+		e.Value = entry
+		o.order.MoveToFront(e)
+		return nil
+	}
+
+	o.entries[userID] = o.order.PushFront(entry)
+	for o.order.Len() > o.capacity {
+		o.remove(o.order.Back())
+	}
+	return nil
+}
+
+func (o *MemoryEasyPayMethodCache) Delete(userID int) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if e, exists := o.entries[userID]; exists {
+		o.remove(e)
+	}
+	return nil
+}
+
+func (o *MemoryEasyPayMethodCache) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.order.Len()
+}
+
+func (o *MemoryEasyPayMethodCache) remove(e *list.Element) {
+	o.order.Remove(e)
+	delete(o.entries, e.Value.(*easyPayMethodCacheEntry).userID)
+}
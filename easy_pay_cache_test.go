@@ -0,0 +1,121 @@
+package payletter
+
+import (
+	"testing"
+	"time"
+)
+
+// deletingPayLetter 결제 수단 삭제만 지원하는 fake
+type deletingPayLetter struct {
+	*fakePayLetter
+}
+
+func (o deletingPayLetter) DeleteEasyPayMethod(req ReqDeleteEasyPayMethod) (ResUpdateEasyPayMethod, error) {
+	o.called("DeleteEasyPayMethod")
+	return ResUpdateEasyPayMethod{}, nil
+}
+
+func TestCachedEasyPayMethodPayLetter(t *testing.T) {
+	methods := []EasyPayMethod{{PaymentMethod: PgCodeCreditCard, BillKey: "billkey-1"}}
+	p := &fakePayLetter{
+		getEasyPayMethods: func(req ReqGetRegisteredEasyPayMethod) (ResPayLetterGetEasyPayMethods, error) {
+			return ResPayLetterGetEasyPayMethods{MethodList: methods}, nil
+		},
+	}
+	cached := GetCachedEasyPayMethodPayLetter(deletingPayLetter{p}, NewMemoryEasyPayMethodCache(10))
+
+	for i := 0; i < 3; i++ {
+		res, err := cached.GetRegisteredEasyPayMethods(ReqGetRegisteredEasyPayMethod{UserID: 1})
+		if err != nil || len(res.MethodList) != 1 {
+			t.Fatalf("res %+v, err %v", res, err)
+		}
+		res.MethodList[0].BillKey = "modified" // 반환한 목록을 수정해도 캐시는 그대로
+	}
+	if p.count("GetRegisteredEasyPayMethods") != 1 {
+		t.Fatalf("GetRegisteredEasyPayMethods %d 회 호출", p.count("GetRegisteredEasyPayMethods"))
+	}
+
+	// 결제 수단 삭제 후에는 다시 조회
+	methods = nil
+	if _, err := cached.DeleteEasyPayMethod(ReqDeleteEasyPayMethod{UserID: 1, BillKey: "billkey-1"}); err != nil {
+		t.Fatal(err)
+	}
+	res, _ := cached.GetRegisteredEasyPayMethods(ReqGetRegisteredEasyPayMethod{UserID: 1})
+	if len(res.MethodList) != 0 || p.count("GetRegisteredEasyPayMethods") != 2 {
+		t.Fatalf("삭제 후 res %+v, 조회 %d 회", res, p.count("GetRegisteredEasyPayMethods"))
+	}
+}
+
+func TestCachedEasyPayMethodPayLetterInvalidateDuringFetch(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	calls := 0
+	p := &fakePayLetter{
+		getEasyPayMethods: func(req ReqGetRegisteredEasyPayMethod) (ResPayLetterGetEasyPayMethods, error) {
+			calls++
+			if calls == 1 {
+				close(started)
+				<-release
+				return ResPayLetterGetEasyPayMethods{MethodList: []EasyPayMethod{{BillKey: "deleted"}}}, nil
+			}
+			return ResPayLetterGetEasyPayMethods{}, nil
+		},
+	}
+	cache := NewMemoryEasyPayMethodCache(10)
+	cached := GetCachedEasyPayMethodPayLetter(p, cache)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = cached.GetRegisteredEasyPayMethods(ReqGetRegisteredEasyPayMethod{UserID: 1})
+	}()
+
+	// 삭제 전 목록을 조회하는 중에 결제 수단이 삭제됨
+	<-started
+	cached.Invalidate(1)
+	close(release)
+	<-done
+
+	if _, found, _ := cache.Get(1); found {
+		t.Fatal("Invalidate 이전에 시작한 조회 결과가 캐시됨")
+	}
+	res, _ := cached.GetRegisteredEasyPayMethods(ReqGetRegisteredEasyPayMethod{UserID: 1})
+	if len(res.MethodList) != 0 {
+		t.Fatalf("삭제된 결제 수단이 조회됨 %+v", res.MethodList)
+	}
+	if len(cached.fetches) != 0 {
+		t.Fatalf("완료된 조회 %d 건이 남음", len(cached.fetches))
+	}
+}
+
+func TestMemoryEasyPayMethodCache(t *testing.T) {
+	clock := newFixedClock(time.Date(2024, 3, 1, 12, 0, 0, 0, kst))
+	cache := NewMemoryEasyPayMethodCache(2, WithClock(clock.Now))
+	res := ResPayLetterGetEasyPayMethods{TotalCount: 1}
+
+	_ = cache.Set(1, res, time.Minute)
+	_ = cache.Set(2, res, time.Minute)
+	if _, found, _ := cache.Get(1); !found { // 1 이 최근 사용
+		t.Fatal("user 1 없음")
+	}
+	_ = cache.Set(3, res, time.Minute)
+	if _, found, _ := cache.Get(2); found {
+		t.Fatal("가장 오래 사용하지 않은 user 2 가 남음")
+	}
+	if cache.Len() != 2 {
+		t.Fatalf("Len %d", cache.Len())
+	}
+
+	_ = cache.Delete(3)
+	if _, found, _ := cache.Get(3); found {
+		t.Fatal("삭제한 user 3 이 남음")
+	}
+
+	clock.Advance(time.Minute)
+	if _, found, _ := cache.Get(1); found {
+		t.Fatal("만료된 user 1 이 조회됨")
+	}
+	if cache.Len() != 0 {
+		t.Fatalf("만료 후 Len %d", cache.Len())
+	}
+}
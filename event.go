@@ -0,0 +1,206 @@
+package payletter
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PaymentEvent 다른 서비스로 발행하는 결제 이벤트, 소비자는 ID 로 중복 수신을 걸러야 함
+type PaymentEvent struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	OrderNo    string          `json:"order_no"`
+	TID        string          `json:"tid"`
+	UserID     int64           `json:"user_id"`
+	Payload    json.RawMessage `json:"payload"`
+	OccurredAt time.Time       `json:"occurred_at"`
+}
+
+// Decode Payload 를 Type 에 맞는 payload 구조체로 변환
+func (o PaymentEvent) Decode(v any) error {
+	return json.Unmarshal(o.Payload, v)
+}
+
+type PaymentApprovedPayload struct {
+	PgCode          PgCode `json:"pgcode"`
+	UserID          int64  `json:"user_id"`
+	OrderNo         string `json:"order_no"`
+	TID             string `json:"tid"`
+	CID             string `json:"cid"`
+	Amount          int    `json:"amount"`
+	TaxAmount       int    `json:"tax_amount"`
+	TaxFreeAmount   int    `json:"taxfree_amount"`
+	MaskedBillKey   string `json:"masked_billkey,omitempty"`
+	CardCode        string `json:"card_code,omitempty"`
+	PayInfo         string `json:"pay_info,omitempty"`
+	TransactionDate string `json:"transaction_date"`
+}
+
+type PaymentCancelledPayload struct {
+	PgCode  PgCode `json:"pgcode"`
+	UserID  int64  `json:"user_id"`
+	OrderNo string `json:"order_no"`
+	TID     string `json:"tid"`
+	CID     string `json:"cid"`
+	Amount  int    `json:"amount"`
+}
+
+type PartialRefundedPayload struct {
+	PgCode  PgCode `json:"pgcode"`
+	UserID  int64  `json:"user_id"`
+	OrderNo string `json:"order_no"`
+	TID     string `json:"tid"`
+	CID     string `json:"cid"`
+	Amount  int    `json:"amount"` // 이번에 환불한 금액
+}
+
+// BillKeyIssuedPayload 이벤트는 여러 서비스와 broker 에 남으므로 billkey 원문 대신 마스킹한 값과 BillKeyVault record id 만 담음
+type BillKeyIssuedPayload struct {
+	PgCode        PgCode `json:"pgcode"`
+	UserID        int64  `json:"user_id"`
+	OrderNo       string `json:"order_no"`
+	BillKeyID     string `json:"billkey_id"` // BillKeyVault 에 저장한 record id
+	MaskedBillKey string `json:"masked_billkey"`
+	CardCode      string `json:"card_code"`
+	CardInfo      string `json:"card_info"`
+}
+
+// EasyPayMethodRegisteredPayload billkey 는 BillKeyIssuedPayload 와 같이 마스킹한 값과 record id 만 담음
+type EasyPayMethodRegisteredPayload struct {
+	UserID        int64  `json:"user_id"`
+	PaymentMethod PgCode `json:"payment_method"`
+	BillKeyID     string `json:"billkey_id"` // BillKeyVault 에 저장한 record id
+	MaskedBillKey string `json:"masked_billkey"`
+	MethodCode    string `json:"method_code"`
+	MethodName    string `json:"method_name"`
+	MethodInfo    string `json:"method_info"`
+}
+
+// NewPaymentApprovedEvent TransactionAutoPay 성공 이벤트
+func NewPaymentApprovedEvent(req ReqTransactionAutoPay, res ResTransactionAutoPay) (PaymentEvent, error) {
+	return newPaymentEvent(PaymentEventType.PaymentApproved, req.OrderNo, res.TID, req.UserID, PaymentApprovedPayload{
+		PgCode:          req.PgCode,
+		UserID:          req.UserID,
+		OrderNo:         req.OrderNo,
+		TID:             res.TID,
+		CID:             res.CID,
+		Amount:          res.Amount,
+		TaxAmount:       req.TaxAmount.Int(),
+		TaxFreeAmount:   req.TaxFreeAmount.Int(),
+		MaskedBillKey:   MaskBillKey(res.BillKey),
+		TransactionDate: res.TransactionDate,
+	})
+}
+
+// NewPaymentApprovedEventFromCallback 결제 callback 수신 이벤트
+func NewPaymentApprovedEventFromCallback(data ResPaymentData) (PaymentEvent, error) {
+	userID := parseEventUserID(data.UserID)
+	return newPaymentEvent(PaymentEventType.PaymentApproved, data.OrderNo, data.Tid, userID, PaymentApprovedPayload{
+		PgCode:          data.PgCode,
+		UserID:          userID,
+		OrderNo:         data.OrderNo,
+		TID:             data.Tid,
+		CID:             data.Cid,
+		Amount:          data.Amount,
+		TaxAmount:       data.TaxAmount,
+		TaxFreeAmount:   data.TaxFreeAmount,
+		MaskedBillKey:   MaskBillKey(data.BillKey),
+		CardCode:        data.CardCode,
+		PayInfo:         data.PayInfo,
+		TransactionDate: data.TransactionDate,
+	})
+}
+
+// NewPaymentCancelledEvent 취소 요청에는 주문번호가 없으므로 취소한 Payment 의 OrderNo 를 받음
+func NewPaymentCancelledEvent(orderNo string, req ReqCancelTransaction, res ResCancelTransaction) (PaymentEvent, error) {
+	return newPaymentEvent(PaymentEventType.PaymentCancelled, orderNo, res.TID, req.UserID, PaymentCancelledPayload{
+		PgCode:  req.PgCode,
+		UserID:  req.UserID,
+		OrderNo: orderNo,
+		TID:     res.TID,
+		CID:     res.CID,
+		Amount:  res.Amount,
+	})
+}
+
+// NewPartialRefundedEvent orderNo 는 부분 취소한 Payment 의 OrderNo
+func NewPartialRefundedEvent(orderNo string, req ReqPartialCancelTransaction, res ResPartialCancelTransaction) (PaymentEvent, error) {
+	return newPaymentEvent(PaymentEventType.PartialRefunded, orderNo, res.TID, req.UserID, PartialRefundedPayload{
+		PgCode:  req.PgCode,
+		UserID:  req.UserID,
+		OrderNo: orderNo,
+		TID:     res.TID,
+		CID:     res.CID,
+		Amount:  res.Amount,
+	})
+}
+
+// NewBillKeyIssuedEvent 자동 결제 등록 callback 으로 billkey 를 발급 받은 이벤트, billKeyID 는 BillKeyVault.Put 으로 저장한 record id
+func NewBillKeyIssuedEvent(data ResPaymentData, billKeyID string) (PaymentEvent, error) {
+	userID := parseEventUserID(data.UserID)
+	return newPaymentEvent(PaymentEventType.BillKeyIssued, data.OrderNo, data.Tid, userID, BillKeyIssuedPayload{
+		PgCode:        data.PgCode,
+		UserID:        userID,
+		OrderNo:       data.OrderNo,
+		BillKeyID:     billKeyID,
+		MaskedBillKey: MaskBillKey(data.BillKey),
+		CardCode:      data.CardCode,
+		CardInfo:      data.CardInfo,
+	})
+}
+
+// NewEasyPayMethodRegisteredEvent billKeyID 는 BillKeyVault.Put 으로 저장한 record id
+func NewEasyPayMethodRegisteredEvent(userID int, method EasyPayMethod, billKeyID string) (PaymentEvent, error) {
+	return newPaymentEvent(PaymentEventType.EasyPayMethodRegistered, "", "", int64(userID), EasyPayMethodRegisteredPayload{
+		UserID:        int64(userID),
+		PaymentMethod: method.PaymentMethod,
+		BillKeyID:     billKeyID,
+		MaskedBillKey: MaskBillKey(method.BillKey),
+		MethodCode:    method.MethodCode,
+		MethodName:    method.MethodName,
+		MethodInfo:    method.MethodInfo,
+	})
+}
+
+func newPaymentEvent(eventType, orderNo, tid string, userID int64, payload any) (PaymentEvent, error) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return PaymentEvent{}, fmt.Errorf("[%s]payload 변환 실패: %w", eventType, err)
+	}
+
+	return PaymentEvent{
+		ID:         newEventID(),
+		Type:       eventType,
+		OrderNo:    orderNo,
+		TID:        tid,
+		UserID:     userID,
+		Payload:    b,
+		OccurredAt: time.Now(),
+	}, nil
+}
+
+// MaskBillKey 마지막 4자리만 남기고 가림
+func MaskBillKey(billKey string) string {
+	if len(billKey) <= 4 {
+		return strings.Repeat("*", len(billKey))
+	}
+	return strings.Repeat("*", len(billKey)-4) + billKey[len(billKey)-4:]
+}
+
+func newEventID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+func parseEventUserID(userID string) int64 {
+	id, _ := strconv.ParseInt(userID, 10, 64)
+	return id
+}
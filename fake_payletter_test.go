@@ -0,0 +1,100 @@
+package payletter
+
+import (
+	"sync"
+	"time"
+)
+
+// fakePayLetter 테스트에서 필요한 메서드만 함수로 지정, 지정하지 않은 메서드를 호출하면 panic
+type fakePayLetter struct {
+	IPayLetter
+
+	mu    sync.Mutex
+	calls map[string]int
+
+	transactionAutoPay func(req ReqTransactionAutoPay) (ResTransactionAutoPay, error)
+	transactionEasyPay func(req ReqTransactionEasyPay) (ResEasyPayUI, error)
+	cancelTransaction  func(req ReqCancelTransaction) (ResCancelTransaction, error)
+	partialCancel      func(req ReqPartialCancelTransaction) (ResPartialCancelTransaction, error)
+	cancelEasyPay      func(req ReqCancelEasyPay) (ResCancelEasyPay, error)
+	getTransactionList func(req ReqGetTransactionList) (ResGetTransactionList, error)
+	getTransaction     func(req ReqGetTransaction) (ResGetTransaction, error)
+	getEasyPayMethods  func(req ReqGetRegisteredEasyPayMethod) (ResPayLetterGetEasyPayMethods, error)
+}
+
+func (o *fakePayLetter) called(name string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.calls == nil {
+		o.calls = make(map[string]int)
+	}
+	o.calls[name]++
+}
+
+func (o *fakePayLetter) count(name string) int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.calls[name]
+}
+
+func (o *fakePayLetter) TransactionAutoPay(req ReqTransactionAutoPay) (ResTransactionAutoPay, error) {
+	o.called("TransactionAutoPay")
+	return o.transactionAutoPay(req)
+}
+
+func (o *fakePayLetter) TransactionEasyPay(req ReqTransactionEasyPay) (ResEasyPayUI, error) {
+	o.called("TransactionEasyPay")
+	return o.transactionEasyPay(req)
+}
+
+func (o *fakePayLetter) CancelTransaction(req ReqCancelTransaction) (ResCancelTransaction, error) {
+	o.called("CancelTransaction")
+	return o.cancelTransaction(req)
+}
+
+func (o *fakePayLetter) PartialCancelTransaction(req ReqPartialCancelTransaction) (ResPartialCancelTransaction, error) {
+	o.called("PartialCancelTransaction")
+	return o.partialCancel(req)
+}
+
+func (o *fakePayLetter) CancelEasyPay(req ReqCancelEasyPay) (ResCancelEasyPay, error) {
+	o.called("CancelEasyPay")
+	return o.cancelEasyPay(req)
+}
+
+func (o *fakePayLetter) GetTransactionList(req ReqGetTransactionList) (ResGetTransactionList, error) {
+	o.called("GetTransactionList")
+	return o.getTransactionList(req)
+}
+
+func (o *fakePayLetter) GetTransaction(req ReqGetTransaction) (ResGetTransaction, error) {
+	o.called("GetTransaction")
+	return o.getTransaction(req)
+}
+
+func (o *fakePayLetter) GetRegisteredEasyPayMethods(req ReqGetRegisteredEasyPayMethod) (ResPayLetterGetEasyPayMethods, error) {
+	o.called("GetRegisteredEasyPayMethods")
+	return o.getEasyPayMethods(req)
+}
+
+// fixedClock 고정 시각을 돌려주는 Clock, 필요하면 테스트 중에 이동
+type fixedClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func newFixedClock(t time.Time) *fixedClock {
+	return &fixedClock{t: t}
+}
+
+func (o *fixedClock) Now() time.Time {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.t
+}
+
+func (o *fixedClock) Advance(d time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.t = o.t.Add(d)
+}
@@ -0,0 +1,417 @@
+package payletter
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	ErrIdempotencyInProgress  = errors.New("같은 idempotency key 요청이 처리 중")
+	ErrIdempotencyKeyMismatch = errors.New("idempotency key 가 다른 요청에 재사용됨")
+)
+
+const defaultIdempotencyLockTTL = time.Minute
+
+type IdempotencyRecord struct {
+	Key         string
+	RequestHash string
+	Done        bool
+	Result      []byte // 첫 응답 json
+	ErrCode     string // 첫 응답의 페이레터 에러 코드
+	ErrMessage  string // 첫 응답 에러
+	LockedUntil time.Time
+	CreatedAt   time.Time
+}
+
+type IIdempotencyStore interface {
+	// Acquire key 선점
+	//  - 처음 보는 key 이면 acquired = true
+	//  - 이미 완료된 key 이면 저장된 record
+	//  - 처리 중인 key 이면 ErrIdempotencyInProgress (lockTTL 이 지나면 다시 선점 가능)
+	//  - 다른 요청으로 사용된 key 이면 ErrIdempotencyKeyMismatch
+	Acquire(key, requestHash string, lockTTL time.Duration) (record IdempotencyRecord, acquired bool, err error)
+	// Complete 첫 처리 결과 저장, 에러 응답이면 페이레터 에러 코드와 메시지
+	Complete(key string, result []byte, errCode, errMessage string) error
+	// Release 처리하지 못한 key 를 반납해 재시도 가능하게 함
+	Release(key string) error
+	// Extend 처리 중인 key 의 잠금을 지금부터 lockTTL 동안 연장
+	Extend(key string, lockTTL time.Duration) error
+}
+
+// IdempotentPayLetter 같은 key 의 결제, 취소 요청을 한번만 실행하고 이후에는 첫 결과를 반환
+//
+// 요청 검증 실패와 서킷 open 은 key 를 반납해 다시 요청할 수 있고,
+// 결과를 알 수 없는 에러(페이레터 장애, 통신 에러, panic)는 LockTTL 동안 ErrIdempotencyInProgress
+type IdempotentPayLetter struct {
+	IPayLetter
+	Store   IIdempotencyStore
+	LockTTL time.Duration // 처리 중 잠금 유지 시간, 요청 중에는 LockTTL/2 마다 연장, 0 이면 1분
+}
+
+func GetIdempotentPayLetter(p IPayLetter, store IIdempotencyStore) *IdempotentPayLetter {
+	return &IdempotentPayLetter{
+		IPayLetter: p,
+		Store:      store,
+	}
+}
+
+// TransactionAutoPay OrderNo 를 key 로 사용
+func (o *IdempotentPayLetter) TransactionAutoPay(req ReqTransactionAutoPay) (res ResTransactionAutoPay, err error) {
+	return o.TransactionAutoPayWithKey("", req)
+}
+
+// TransactionAutoPayWithKey key 가 비어 있으면 OrderNo 를 key 로 사용
+func (o *IdempotentPayLetter) TransactionAutoPayWithKey(key string, req ReqTransactionAutoPay) (res ResTransactionAutoPay, err error) {
+	if key == "" {
+		key = req.OrderNo
+	}
+	return doIdempotent(o, Operation.TransactionAutoPay, key, req, o.IPayLetter.TransactionAutoPay)
+}
+
+// CancelTransaction TID 를 key 로 사용
+func (o *IdempotentPayLetter) CancelTransaction(req ReqCancelTransaction) (res ResCancelTransaction, err error) {
+	return o.CancelTransactionWithKey("", req)
+}
+
+// CancelTransactionWithKey key 가 비어 있으면 TID 를 key 로 사용
+func (o *IdempotentPayLetter) CancelTransactionWithKey(key string, req ReqCancelTransaction) (res ResCancelTransaction, err error) {
+	if key == "" {
+		key = req.TID
+	}
+	return doIdempotent(o, Operation.CancelTransaction, key, req, o.IPayLetter.CancelTransaction)
+}
+
+func doIdempotent[Req any, Res any](o *IdempotentPayLetter, operation, key string, req Req, fn func(Req) (Res, error)) (res Res, err error) {
+	if key == "" {
+		err = errors.New("idempotency key 없음")
+		return
+	}
+	key = operation + ":" + key
+
+	lockTTL := o.LockTTL
+	if lockTTL <= 0 {
+		lockTTL = defaultIdempotencyLockTTL
+	}
+
+	reqJson, err := json.Marshal(req)
+	if err != nil {
+		return
+	}
+	h := sha256.Sum256(reqJson)
+
+	record, acquired, err := o.Store.Acquire(key, hex.EncodeToString(h[:]), lockTTL)
+	if err != nil {
+		return
+	}
+
+	if !acquired {
+		if err = json.Unmarshal(record.Result, &res); err != nil {
+			return
+		}
+		err = record.err()
+		return
+	}
+
+	// 처리 중 잠금이 만료되어 다른 요청이 같은 key 로 중복 결제하지 않도록 주기적으로 연장
+	stop := keepLocked(o.Store, key, lockTTL)
+	defer stop()
+
+	res, err = fn(req)
+	stop()
+
+	// 페이레터를 호출하지 않은 에러만 key 를 반납해 재시도 가능하게 함
+	if isLocalError(err) || errors.Is(err, ErrPayLetterUnavailable) {
+		_ = o.Store.Release(key)
+		return
+	}
+
+	// 페이레터 장애, 통신 에러, panic 은 이미 결제, 취소되었을 수 있으므로 잠금을 유지해
+	// lockTTL 이 지나기 전의 재시도는 ErrIdempotencyInProgress, 그 사이에 GetTransaction 으로 결과를 확인해야 함
+	var e *PayLetterError
+	if err != nil && (!errors.As(err, &e) || e.Server) {
+		return
+	}
+
+	resJson, marshalErr := json.Marshal(res)
+	if marshalErr != nil {
+		return
+	}
+
+	errCode, errMessage := "", ""
+	if e != nil {
+		errCode, errMessage = e.Code, e.Message
+	}
+	// 저장에 실패하면 잠금이 남아 lockTTL 만료를 기다림
+	_ = o.Store.Complete(key, resJson, errCode, errMessage)
+
+	return
+}
+
+// keepLocked 처리 중인 key 의 잠금을 lockTTL/2 마다 연장, 반환한 함수로 중지 (여러 번 호출 가능)
+func keepLocked(store IIdempotencyStore, key string, lockTTL time.Duration) (stop func()) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(lockTTL / 2)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				_ = store.Extend(key, lockTTL) // 실패하면 다음 주기에 재시도
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-stopped
+		})
+	}
+}
+
+// err 저장된 첫 응답 에러, 페이레터 에러 응답은 PayLetterError 로 복원
+func (o IdempotencyRecord) err() error {
+	if o.ErrCode != "" {
+		return &PayLetterError{Code: o.ErrCode, Message: o.ErrMessage}
+	}
+	if o.ErrMessage != "" {
+		return errors.New(o.ErrMessage)
+	}
+	return nil
+}
+
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]IdempotencyRecord
+}
+
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		records: make(map[string]IdempotencyRecord),
+	}
+}
+
+func (o *MemoryIdempotencyStore) Acquire(key, requestHash string, lockTTL time.Duration) (record IdempotencyRecord, acquired bool, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	now := time.Now()
+	record, exists := o.records[key]
+	if !exists {
+		record = IdempotencyRecord{
+			Key:         key,
+			RequestHash: requestHash,
+			LockedUntil: now.Add(lockTTL),
+			CreatedAt:   now,
+		}
+		o.records[key] = record
+		acquired = true
+		return
+	}
+
+	if record.RequestHash != requestHash {
+		err = ErrIdempotencyKeyMismatch
+		return
+	}
+	if record.Done {
+		return
+	}
+	if now.Before(record.LockedUntil) {
+		err = ErrIdempotencyInProgress
+		return
+	}
+
+	// 이전 처리자의 잠금 만료
+	record.LockedUntil = now.Add(lockTTL)
+	o.records[key] = record
+	acquired = true
+	return
+}
+
+func (o *MemoryIdempotencyStore) Complete(key string, result []byte, errCode, errMessage string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	record, exists := o.records[key]
+	if !exists {
+		return fmt.Errorf("idempotency key %s 없음", key)
+	}
+
+	record.Done = true
+	record.Result = result
+	record.ErrCode = errCode
+	record.ErrMessage = errMessage
+	record.LockedUntil = time.Time{}
+	o.records[key] = record
+	return nil
+}
+
+func (o *MemoryIdempotencyStore) Extend(key string, lockTTL time.Duration) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	record, exists := o.records[key]
+	if !exists || record.Done {
+		return fmt.Errorf("처리 중인 idempotency key %s 없음", key)
+	}
+	record.LockedUntil = time.Now().Add(lockTTL)
+	o.records[key] = record
+	return nil
+}
+
+func (o *MemoryIdempotencyStore) Release(key string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if record, exists := o.records[key]; exists && !record.Done {
+		delete(o.records, key)
+	}
+	return nil
+}
+
+// IdempotencyTableDDL SQLIdempotencyStore 용 테이블 (MySQL 기준)
+const IdempotencyTableDDL = `CREATE TABLE payletter_idempotency (
+	idem_key     VARCHAR(191) NOT NULL PRIMARY KEY,
+	request_hash CHAR(64)     NOT NULL,
+	done         TINYINT(1)   NOT NULL DEFAULT 0,
+	result       BLOB         NULL,
+	err_code     VARCHAR(50)  NOT NULL DEFAULT '',
+	err_message  TEXT         NOT NULL,
+	locked_until BIGINT       NOT NULL,
+	created_at   BIGINT       NOT NULL
+)`
+
+// SQLIdempotencyStore database/sql 기반 저장소, placeholder 는 ? 를 사용 (MySQL, SQLite)
+type SQLIdempotencyStore struct {
+	DB    *sql.DB
+	Table string // 비어 있으면 payletter_idempotency
+}
+
+func NewSQLIdempotencyStore(db *sql.DB) *SQLIdempotencyStore {
+	return &SQLIdempotencyStore{
+		DB: db,
+	}
+}
+
+func (o *SQLIdempotencyStore) table() string {
+	if o.Table == "" {
+		return "payletter_idempotency"
+	}
+	return o.Table
+}
+
+func (o *SQLIdempotencyStore) Acquire(key, requestHash string, lockTTL time.Duration) (record IdempotencyRecord, acquired bool, err error) {
+	now := time.Now()
+
+	_, err = o.DB.Exec(
+		fmt.Sprintf("INSERT INTO %s (idem_key, request_hash, done, err_code, err_message, locked_until, created_at) VALUES (?, ?, 0, '', '', ?, ?)", o.table()),
+		key, requestHash, now.Add(lockTTL).UnixMilli(), now.UnixMilli(),
+	)
+	if err == nil {
+		acquired = true
+		return
+	}
+
+	// 중복 key 에러 판별은 driver 마다 다르므로 다시 조회해서 판단
+	record, found, selectErr := o.get(key)
+	if selectErr != nil || !found {
+		return
+	}
+	err = nil
+
+	if record.RequestHash != requestHash {
+		err = ErrIdempotencyKeyMismatch
+		return
+	}
+	if record.Done {
+		return
+	}
+	if now.Before(record.LockedUntil) {
+		err = ErrIdempotencyInProgress
+		return
+	}
+
+	// 이전 처리자의 잠금 만료, 동시에 선점하려는 다른 처리자와 경쟁
+	result, err := o.DB.Exec(
+		fmt.Sprintf("UPDATE %s SET locked_until = ? WHERE idem_key = ? AND done = 0 AND locked_until = ?", o.table()),
+		now.Add(lockTTL).UnixMilli(), key, record.LockedUntil.UnixMilli(),
+	)
+	if err != nil {
+		return
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affected == 0 {
+		err = ErrIdempotencyInProgress
+		return
+	}
+
+	acquired = true
+	return
+}
+
+func (o *SQLIdempotencyStore) get(key string) (record IdempotencyRecord, found bool, err error) {
+	var lockedUntil, createdAt int64
+	err = o.DB.QueryRow(
+		fmt.Sprintf("SELECT idem_key, request_hash, done, result, err_code, err_message, locked_until, created_at FROM %s WHERE idem_key = ?", o.table()),
+		key,
+	).Scan(&record.Key, &record.RequestHash, &record.Done, &record.Result, &record.ErrCode, &record.ErrMessage, &lockedUntil, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = nil
+		return
+	}
+	if err != nil {
+		return
+	}
+
+	record.LockedUntil = time.UnixMilli(lockedUntil)
+	record.CreatedAt = time.UnixMilli(createdAt)
+	found = true
+	return
+}
+
+func (o *SQLIdempotencyStore) Complete(key string, result []byte, errCode, errMessage string) (err error) {
+	_, err = o.DB.Exec(
+		fmt.Sprintf("UPDATE %s SET done = 1, result = ?, err_code = ?, err_message = ?, locked_until = 0 WHERE idem_key = ?", o.table()),
+		result, errCode, errMessage, key,
+	)
+	return
+}
+
+func (o *SQLIdempotencyStore) Extend(key string, lockTTL time.Duration) (err error) {
+	result, err := o.DB.Exec(
+		fmt.Sprintf("UPDATE %s SET locked_until = ? WHERE idem_key = ? AND done = 0", o.table()),
+		time.Now().Add(lockTTL).UnixMilli(), key,
+	)
+	if err != nil {
+		return
+	}
+	affected, err := result.RowsAffected()
+	if err == nil && affected == 0 {
+		err = fmt.Errorf("처리 중인 idempotency key %s 없음", key)
+	}
+	return
+}
+
+func (o *SQLIdempotencyStore) Release(key string) (err error) {
+	_, err = o.DB.Exec(
+		fmt.Sprintf("DELETE FROM %s WHERE idem_key = ? AND done = 0", o.table()),
+		key,
+	)
+	return
+}
@@ -0,0 +1,170 @@
+package payletter
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIdempotentPayLetterReplaysSuccess(t *testing.T) {
+	p := &fakePayLetter{
+		transactionAutoPay: func(req ReqTransactionAutoPay) (ResTransactionAutoPay, error) {
+			return ResTransactionAutoPay{TID: "tid-1", Amount: req.Amount}, nil
+		},
+	}
+	idem := GetIdempotentPayLetter(p, NewMemoryIdempotencyStore())
+	req := ReqTransactionAutoPay{OrderNo: "order-1", Amount: 1000}
+
+	for i := 0; i < 3; i++ {
+		res, err := idem.TransactionAutoPay(req)
+		if err != nil || res.TID != "tid-1" {
+			t.Fatalf("res %+v, err %v", res, err)
+		}
+	}
+	if p.count("TransactionAutoPay") != 1 {
+		t.Fatalf("TransactionAutoPay %d 회 호출", p.count("TransactionAutoPay"))
+	}
+
+	req.Amount = 2000
+	if _, err := idem.TransactionAutoPay(req); !errors.Is(err, ErrIdempotencyKeyMismatch) {
+		t.Fatalf("err = %v, want ErrIdempotencyKeyMismatch", err)
+	}
+}
+
+func TestIdempotentPayLetterReplaysTypedError(t *testing.T) {
+	p := &fakePayLetter{
+		transactionAutoPay: func(req ReqTransactionAutoPay) (ResTransactionAutoPay, error) {
+			return ResTransactionAutoPay{}, &PayLetterError{Code: "1001", Message: "한도 초과"}
+		},
+	}
+	idem := GetIdempotentPayLetter(p, NewMemoryIdempotencyStore())
+	req := ReqTransactionAutoPay{OrderNo: "order-1", Amount: 1000}
+
+	_, _ = idem.TransactionAutoPay(req)
+	_, err := idem.TransactionAutoPay(req)
+	if code, ok := PayLetterErrorCode(err); !ok || code != "1001" {
+		t.Fatalf("재응답 err = %#v", err)
+	}
+	if p.count("TransactionAutoPay") != 1 {
+		t.Fatalf("TransactionAutoPay %d 회 호출", p.count("TransactionAutoPay"))
+	}
+}
+
+func TestIdempotentPayLetterReleasesLocalErrors(t *testing.T) {
+	errs := []error{
+		&UnavailableError{},
+		&ValidationError{},
+	}
+	for _, local := range errs {
+		fail := true
+		p := &fakePayLetter{
+			transactionAutoPay: func(req ReqTransactionAutoPay) (ResTransactionAutoPay, error) {
+				if fail {
+					return ResTransactionAutoPay{}, local
+				}
+				return ResTransactionAutoPay{TID: "tid-1"}, nil
+			},
+		}
+		idem := GetIdempotentPayLetter(p, NewMemoryIdempotencyStore())
+		req := ReqTransactionAutoPay{OrderNo: "order-1", Amount: 1000}
+
+		if _, err := idem.TransactionAutoPay(req); err == nil {
+			t.Fatal("첫 요청 에러 없음")
+		}
+		fail = false
+		if res, err := idem.TransactionAutoPay(req); err != nil || res.TID != "tid-1" {
+			t.Errorf("%v 이후 재시도: res %+v, err %v", local, res, err)
+		}
+	}
+}
+
+func TestIdempotentPayLetterKeepsLockOnUnknownErrors(t *testing.T) {
+	errs := []error{
+		&PayLetterError{Code: "500", Message: "internal error", Server: true},
+		errors.New("connection reset"),
+	}
+	for _, unknown := range errs {
+		p := &fakePayLetter{
+			transactionAutoPay: func(req ReqTransactionAutoPay) (ResTransactionAutoPay, error) {
+				return ResTransactionAutoPay{}, unknown
+			},
+		}
+		idem := GetIdempotentPayLetter(p, NewMemoryIdempotencyStore())
+		req := ReqTransactionAutoPay{OrderNo: "order-1", Amount: 1000}
+
+		if _, err := idem.TransactionAutoPay(req); err == nil {
+			t.Fatal("첫 요청 에러 없음")
+		}
+		if _, err := idem.TransactionAutoPay(req); !errors.Is(err, ErrIdempotencyInProgress) {
+			t.Errorf("%v 이후 재시도 err = %v, want ErrIdempotencyInProgress", unknown, err)
+		}
+		if p.count("TransactionAutoPay") != 1 {
+			t.Errorf("%v 이후 TransactionAutoPay %d 회 호출", unknown, p.count("TransactionAutoPay"))
+		}
+	}
+}
+
+func TestIdempotentPayLetterKeepsLockOnPanic(t *testing.T) {
+	p := &fakePayLetter{
+		transactionAutoPay: func(req ReqTransactionAutoPay) (ResTransactionAutoPay, error) {
+			panic("unexpected response")
+		},
+	}
+	idem := GetIdempotentPayLetter(p, NewMemoryIdempotencyStore())
+	req := ReqTransactionAutoPay{OrderNo: "order-1", Amount: 1000}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("panic 이 전달되지 않음")
+			}
+		}()
+		_, _ = idem.TransactionAutoPay(req)
+	}()
+
+	if _, err := idem.TransactionAutoPay(req); !errors.Is(err, ErrIdempotencyInProgress) {
+		t.Fatalf("panic 이후 재시도 err = %v, want ErrIdempotencyInProgress", err)
+	}
+	if p.count("TransactionAutoPay") != 1 {
+		t.Fatalf("TransactionAutoPay %d 회 호출", p.count("TransactionAutoPay"))
+	}
+}
+
+func TestIdempotentPayLetterExtendsLockDuringSlowCall(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	p := &fakePayLetter{
+		transactionAutoPay: func(req ReqTransactionAutoPay) (ResTransactionAutoPay, error) {
+			close(started)
+			<-release
+			return ResTransactionAutoPay{TID: "tid-1"}, nil
+		},
+	}
+	idem := GetIdempotentPayLetter(p, NewMemoryIdempotencyStore())
+	idem.LockTTL = 20 * time.Millisecond
+	req := ReqTransactionAutoPay{OrderNo: "order-1", Amount: 1000}
+
+	done := make(chan error)
+	go func() {
+		_, err := idem.TransactionAutoPay(req)
+		done <- err
+	}()
+	<-started
+
+	// LockTTL 이 몇 번 지나도 연장되어 두번째 요청은 실행되지 않음
+	time.Sleep(5 * idem.LockTTL)
+	if _, err := idem.TransactionAutoPay(req); !errors.Is(err, ErrIdempotencyInProgress) {
+		t.Fatalf("처리 중 재요청 err = %v, want ErrIdempotencyInProgress", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("첫 요청 err = %v", err)
+	}
+	if res, err := idem.TransactionAutoPay(req); err != nil || res.TID != "tid-1" {
+		t.Fatalf("완료 후 재요청: res %+v, err %v", res, err)
+	}
+	if p.count("TransactionAutoPay") != 1 {
+		t.Fatalf("TransactionAutoPay %d 회 호출", p.count("TransactionAutoPay"))
+	}
+}
@@ -0,0 +1,137 @@
+package payletter
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultMaxInstallMonth 결제 수단 정보에 할부 개월 수 목록이 없을 때 허용하는 최대 개월 수
+const defaultMaxInstallMonth = 12
+
+// InstallmentPolicy 간편결제 결제 수단의 할부 정책
+type InstallmentPolicy struct {
+	Enabled    bool  `json:"enabled"`
+	MinAmount  int   `json:"min_amount"`  // 할부 가능 최소 결제 금액
+	Months     []int `json:"months"`      // 할부 가능 개월 수, 오름차순
+	FreeMonths []int `json:"free_months"` // 무이자 할부 개월 수, 오름차순
+}
+
+type InstallmentOption struct {
+	Month        int  `json:"month"`
+	InterestFree bool `json:"interest_free"`
+}
+
+// InstallmentPolicy 결제 수단 정보의 할부 field 를 해석
+//
+// 할부 개월 수 목록이 비어 있으면 2 ~ 12 개월 모두 가능한 것으로 봄, 목록이 있으면 12 개월 초과 (18, 24, 36 개월) 도 그대로 사용
+func (o *EasyPayMethod) InstallmentPolicy() (policy InstallmentPolicy, err error) {
+	policy.Enabled = o.InstallmentUseFlag == "Y" && o.PaymentMethod.Capability().Installment
+	policy.MinAmount = o.MinInstallmentAmount
+
+	if policy.Months, err = parseInstallmentMonths(o.InstallmentMonths); err != nil {
+		err = fmt.Errorf("[%s]installment_months: %w", o.BillKey, err)
+		return
+	}
+	if policy.FreeMonths, err = parseInstallmentMonths(o.FreeInstallmentMonths); err != nil {
+		err = fmt.Errorf("[%s]free_installment_months: %w", o.BillKey, err)
+		return
+	}
+
+	if policy.Enabled && len(policy.Months) == 0 {
+		for month := 2; month <= defaultMaxInstallMonth; month++ {
+			policy.Months = append(policy.Months, month)
+		}
+	}
+	return
+}
+
+// parseInstallmentMonths "2,3,6" 또는 "2:3:6" 형식, 1 이하(일시불)는 무시
+func parseInstallmentMonths(value string) (months []int, err error) {
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ':' || r == '|' || r == ' '
+	})
+
+	seen := make(map[int]bool, len(fields))
+	for _, field := range fields {
+		month, convErr := strconv.Atoi(strings.TrimSpace(field))
+		if convErr != nil || month > maxInstallMonth {
+			return nil, fmt.Errorf("유효하지 않은 할부 개월 수 %q", field)
+		}
+		if month < 2 || seen[month] {
+			continue
+		}
+		seen[month] = true
+		months = append(months, month)
+	}
+	sort.Ints(months)
+	return
+}
+
+// Options amount 결제 시 선택 가능한 할부 개월 수, 할부 불가면 빈 값 (일시불만 가능)
+func (p InstallmentPolicy) Options(amount int) []InstallmentOption {
+	if !p.Enabled || amount < p.MinAmount {
+		return nil
+	}
+
+	options := make([]InstallmentOption, 0, len(p.Months))
+	for _, month := range p.Months {
+		options = append(options, InstallmentOption{
+			Month:        month,
+			InterestFree: p.isInterestFree(month),
+		})
+	}
+	return options
+}
+
+// IsInterestFree month 가 amount 결제 시 무이자 할부인지 여부
+func (p InstallmentPolicy) IsInterestFree(month, amount int) bool {
+	return p.Check(month, amount) == nil && p.isInterestFree(month)
+}
+
+func (p InstallmentPolicy) isInterestFree(month int) bool {
+	for _, m := range p.FreeMonths {
+		if m == month {
+			return true
+		}
+	}
+	return false
+}
+
+// Check month 개월 할부로 amount 를 결제할 수 있는지 검사, 0(일시불)은 항상 가능
+func (p InstallmentPolicy) Check(month, amount int) error {
+	if month == 0 {
+		return nil
+	}
+	if f := installMonth("install_month", month); f != nil {
+		return validate(rules(f))
+	}
+	if !p.Enabled {
+		return validate(rules(fieldError("install_month", "할부를 지원하지 않는 결제 수단")))
+	}
+	if amount < p.MinAmount {
+		return validate(rules(fieldError("install_month", "%s 이상 결제 시 할부 가능", KRW(p.MinAmount))))
+	}
+	for _, m := range p.Months {
+		if m == month {
+			return nil
+		}
+	}
+	return validate(rules(fieldError("install_month", "%d 개월 할부 불가 (가능: %v)", month, p.Months)))
+}
+
+// CheckInstallment 결제 요청의 할부 개월 수를 결제 수단 할부 정책으로 검사
+//
+// TransactionEasyPay 는 결제 수단을 조회하지 않으므로 할부 결제 전에 GetRegisteredEasyPayMethods 로 찾은 결제 수단으로 검사
+func CheckInstallment(method EasyPayMethod, req ReqTransactionEasyPay) error {
+	if req.BillKey != method.BillKey {
+		return validate(rules(fieldError("billkey", "결제 수단 %s 과 요청 billkey 불일치", method.BillKey)))
+	}
+
+	policy, err := method.InstallmentPolicy()
+	if err != nil {
+		return err
+	}
+	return policy.Check(req.InstallMonth, req.Amount)
+}
@@ -0,0 +1,84 @@
+package payletter
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseInstallmentMonths(t *testing.T) {
+	months, err := parseInstallmentMonths("2,3,6,12,18,24,36")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{2, 3, 6, 12, 18, 24, 36}; !reflect.DeepEqual(months, want) {
+		t.Fatalf("months = %v, want %v", months, want)
+	}
+
+	if months, _ = parseInstallmentMonths("0:1:6:3:3"); !reflect.DeepEqual(months, []int{3, 6}) {
+		t.Fatalf("months = %v", months)
+	}
+	if _, err = parseInstallmentMonths("2,x"); err == nil {
+		t.Fatal("숫자가 아닌 개월 수 허용")
+	}
+}
+
+func TestInstallmentPolicy(t *testing.T) {
+	method := EasyPayMethod{
+		PaymentMethod:         PgCodeCreditCard,
+		BillKey:               "billkey",
+		InstallmentUseFlag:    "Y",
+		MinInstallmentAmount:  50000,
+		InstallmentMonths:     "2,3,6,24",
+		FreeInstallmentMonths: "2,3",
+	}
+	policy, err := method.InstallmentPolicy()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = policy.Check(24, 100000); err != nil {
+		t.Fatalf("24 개월: %v", err)
+	}
+	if err = policy.Check(12, 100000); !errors.Is(err, ErrValidation) {
+		t.Fatalf("목록에 없는 개월 수: %v", err)
+	}
+	if err = policy.Check(3, 10000); !errors.Is(err, ErrValidation) {
+		t.Fatalf("최소 금액 미만: %v", err)
+	}
+	if err = policy.Check(0, 10000); err != nil {
+		t.Fatalf("일시불: %v", err)
+	}
+	if !policy.IsInterestFree(3, 100000) || policy.IsInterestFree(6, 100000) {
+		t.Fatal("무이자 할부 판단 오류")
+	}
+
+	method.InstallmentMonths = ""
+	if policy, _ = method.InstallmentPolicy(); len(policy.Months) != 11 || policy.Months[10] != defaultMaxInstallMonth {
+		t.Fatalf("기본 할부 개월 수 %v", policy.Months)
+	}
+}
+
+func TestCheckInstallment(t *testing.T) {
+	method := EasyPayMethod{PaymentMethod: PgCodeCreditCard, BillKey: "billkey", InstallmentUseFlag: "Y", InstallmentMonths: "2,3"}
+	req := ReqTransactionEasyPay{CommonTransactionData: CommonTransactionData{PgCode: PgCodeCreditCard, UserID: 1, Amount: 100000}, BillKey: "billkey"}
+
+	if err := CheckInstallment(method, req); err != nil {
+		t.Fatalf("일시불: %v", err)
+	}
+
+	req.InstallMonth = 3
+	if err := CheckInstallment(method, req); err != nil {
+		t.Fatal(err)
+	}
+
+	req.InstallMonth = 6
+	if err := CheckInstallment(method, req); !errors.Is(err, ErrValidation) {
+		t.Fatalf("정책에 없는 개월 수: %v", err)
+	}
+
+	req.InstallMonth, req.BillKey = 3, "other"
+	if err := CheckInstallment(method, req); !errors.Is(err, ErrValidation) {
+		t.Fatalf("다른 결제 수단: %v", err)
+	}
+}
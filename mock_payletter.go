@@ -6,31 +6,40 @@ import (
 	"github.com/whitecubeinc/go-utils"
 	"net/http"
 	"strconv"
-	"time"
 )
 
 type MockPayLetter struct {
 	ClientInfo
 	Success bool
+	options
 }
 
 // GetSuccessMockPayLetter 무조건 결제 성공하는 Mock pay letter
-func GetSuccessMockPayLetter(c ClientInfo) IPayLetter {
+func GetSuccessMockPayLetter(c ClientInfo, opts ...Option) IPayLetter {
 	return &MockPayLetter{
 		ClientInfo: c,
 		Success:    true,
+		options:    newOptions(opts),
 	}
 }
 
 // GetFailMockPayLetter 무조건 결제 실패하는 Mock pay letter
-func GetFailMockPayLetter(c ClientInfo) IPayLetter {
+func GetFailMockPayLetter(c ClientInfo, opts ...Option) IPayLetter {
 	return &MockPayLetter{
 		ClientInfo: c,
 		Success:    false,
+		options:    newOptions(opts),
 	}
 }
 
 func (o *MockPayLetter) RegisterAutoPay(req ReqRegisterAutoPay) (res ResRegisterAutoPay, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+	if err = checkOperation(Operation.RegisterAutoPay, req.PgCode); err != nil {
+		return
+	}
+
 	paymentData := reqPaymentData{
 		PgCode:          req.PgCode,
 		ClientID:        o.ClientID,
@@ -78,13 +87,20 @@ func (o *MockPayLetter) RegisterAutoPay(req ReqRegisterAutoPay) (res ResRegister
 }
 
 func (o *MockPayLetter) TransactionAutoPay(req ReqTransactionAutoPay) (res ResTransactionAutoPay, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+	if err = checkOperation(Operation.TransactionAutoPay, req.PgCode); err != nil {
+		return
+	}
+
 	if o.Success {
 		res = ResTransactionAutoPay{
 			TID:             "tid",
 			CID:             "cid",
 			Amount:          req.Amount,
 			BillKey:         req.BillKey,
-			TransactionDate: time.Now().String(),
+			TransactionDate: o.now().Format(payletterTimeLayouts[0]),
 		}
 	} else {
 		err = errors.New("fake mock pay letter")
@@ -94,6 +110,10 @@ func (o *MockPayLetter) TransactionAutoPay(req ReqTransactionAutoPay) (res ResTr
 }
 
 func (o *MockPayLetter) CancelTransaction(req ReqCancelTransaction) (res ResCancelTransaction, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
 	if o.Success {
 		res.TID = req.TID
 	} else {
@@ -103,6 +123,13 @@ func (o *MockPayLetter) CancelTransaction(req ReqCancelTransaction) (res ResCanc
 }
 
 func (o *MockPayLetter) PartialCancelTransaction(req ReqPartialCancelTransaction) (res ResPartialCancelTransaction, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+	if err = checkOperation(Operation.PartialCancelTransaction, req.PgCode); err != nil {
+		return
+	}
+
 	if o.Success {
 		res.TID = req.TID
 	} else {
@@ -112,8 +139,13 @@ func (o *MockPayLetter) PartialCancelTransaction(req ReqPartialCancelTransaction
 }
 
 func (o *MockPayLetter) RegisterEasyPay(req ReqRegisterEasyPay) (res ResEasyPayUI, err error) {
+	req.setReqDate(o.reqDate())
+	if err = req.Validate(); err != nil {
+		return
+	}
+
 	req.setClientID(o.ClientID)
-	req.setHashData(o.PaymentAPIKey, o.ClientID)
+	req.setHashData(o.Signer())
 
 	payletterRes := utils.Post[ResEasyPayUI](
 		easyPayRegisterTestUrl,
@@ -135,11 +167,16 @@ func (o *MockPayLetter) RegisterEasyPay(req ReqRegisterEasyPay) (res ResEasyPayU
 }
 
 func (o *MockPayLetter) GetRegisteredEasyPayMethods(req ReqGetRegisteredEasyPayMethod) (res ResPayLetterGetEasyPayMethods, err error) {
+	req.setReqDate(o.reqDate())
+	if err = req.Validate(); err != nil {
+		return
+	}
+
 	params := map[string]string{
 		"client_id": o.ClientID,
 		"user_id":   strconv.Itoa(req.UserID),
 		"req_date":  req.ReqDate,
-		"hash_data": req.createHashData(o.PaymentAPIKey, o.ClientID),
+		"hash_data": req.createHashData(o.Signer()),
 	}
 
 	payletterRes := utils.Get[ResPayLetterGetEasyPayMethods](
@@ -165,9 +202,9 @@ func (o *MockPayLetter) GetRegisteredEasyPayMethods(req ReqGetRegisteredEasyPayM
 
 	for idx, method := range payletterRes.MethodList {
 		switch method.PaymentMethod {
-		case PgCode.CreditCard:
+		case PgCodeCreditCard:
 			method.MethodName = CardCode.ValueMap[method.MethodCode]
-		case PgCode.EasyBank:
+		case PgCodeEasyBank:
 			method.MethodName = BankCode[method.MethodCode]
 		}
 		payletterRes.MethodList[idx] = method
@@ -178,9 +215,14 @@ func (o *MockPayLetter) GetRegisteredEasyPayMethods(req ReqGetRegisteredEasyPayM
 }
 
 func (o *MockPayLetter) CancelEasyPay(req ReqCancelEasyPay) (payLetterRes ResCancelEasyPay, err error) {
+	req.setReqDate(o.reqDate())
+	if err = req.Validate(); err != nil {
+		return
+	}
+
 	req.setClientID(o.ClientID)
 	req.setIPAddress(o.IpAddr)
-	req.setHashData(o.ClientID, o.PaymentAPIKey)
+	req.setHashData(o.Signer())
 
 	payLetterRes = utils.Post[ResCancelEasyPay](
 		easyPayCancelTestUrl,
@@ -199,6 +241,14 @@ func (o *MockPayLetter) CancelEasyPay(req ReqCancelEasyPay) (payLetterRes ResCan
 }
 
 func (o *MockPayLetter) TransactionEasyPay(req ReqTransactionEasyPay) (payLetterRes ResEasyPayUI, err error) {
+	req.setReqDate(o.reqDate())
+	if err = req.Validate(); err != nil {
+		return
+	}
+	if err = checkEasyPayOptions(req); err != nil {
+		return
+	}
+
 	paymentData := reqPaymentData{
 		PgCode:          req.PgCode,
 		ClientID:        o.ClientID,
@@ -217,11 +267,12 @@ func (o *MockPayLetter) TransactionEasyPay(req ReqTransactionEasyPay) (payLetter
 		CallbackUrl:     req.CallbackUrl,
 		CancelUrl:       req.CancelUrl,
 		ReqDate:         req.ReqDate,
-		HashData:        req.createHashData(o.ClientID, o.PaymentAPIKey),
+		HashData:        req.createHashData(o.Signer()),
 		BillKey:         req.BillKey,
 		ReceiptType:     req.ReceiptType,
 		ReceiptInfo:     req.ReceiptInfo,
 		InstallMonth:    fmt.Sprintf("%02d", req.InstallMonth),
+		AmountBreakdown: req.AmountBreakdown,
 	}
 
 	payLetterRes = utils.Post[ResEasyPayUI](
@@ -242,6 +293,10 @@ func (o *MockPayLetter) TransactionEasyPay(req ReqTransactionEasyPay) (payLetter
 }
 
 func (o *MockPayLetter) TransactionNormalPay(req ReqTransactionNormalPay) (payLetterRes ResTransactionNormalPay, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
 	paymentData := reqPaymentData{
 		PgCode:          req.PgCode,
 		ClientID:        o.ClientID,
@@ -258,10 +313,11 @@ func (o *MockPayLetter) TransactionNormalPay(req ReqTransactionNormalPay) (payLe
 		ReturnUrl:       req.ReturnUrl,
 		CallbackUrl:     req.CallbackUrl,
 		CancelUrl:       req.CancelUrl,
+		AmountBreakdown: req.AmountBreakdown,
 	}
 
 	apiKey := o.PaymentAPIKey
-	if req.PgCode == PgCode.NaverPay {
+	if req.PgCode.NeedsSeparateCredentials() {
 		paymentData.ClientID = req.NaverAPIClientId
 		apiKey = req.NaverAPIKey
 	}
@@ -282,8 +338,332 @@ func (o *MockPayLetter) TransactionNormalPay(req ReqTransactionNormalPay) (payLe
 	return
 }
 
-func (o *MockPayLetter) GetTransactionList(_ ReqGetTransactionList) (res ResGetTransactionList, err error) {
+func (o *MockPayLetter) GetTransactionList(req ReqGetTransactionList) (res ResGetTransactionList, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
 	res.TotalCount = 0
 	res.List = make([]Transaction, 0)
 	return
 }
+
+func (o *MockPayLetter) IssueVirtualAccount(req ReqIssueVirtualAccount) (payLetterRes ResTransactionNormalPay, err error) {
+	req.PgCode = PgCodeVirtualAccount
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	paymentData := reqPaymentData{
+		PgCode:          req.PgCode,
+		ClientID:        o.ClientID,
+		ServiceName:     req.ServiceName,
+		UserID:          int64(req.UserID),
+		UserName:        req.UserName,
+		OrderNo:         req.OrderNo,
+		Amount:          req.Amount,
+		ProductName:     req.ProductName,
+		EmailFlag:       req.EmailFlag,
+		EmailAddr:       req.EmailAddr,
+		AutoPayFlag:     "N",
+		CustomParameter: strconv.Itoa(req.CustomParameter),
+		ReturnUrl:       req.ReturnUrl,
+		CallbackUrl:     req.CallbackUrl,
+		CancelUrl:       req.CancelUrl,
+		BankCode:        req.BankCode,
+		DepositorName:   req.DepositorName,
+		AmountBreakdown: req.AmountBreakdown,
+	}
+	if !req.ExpireAt.IsZero() {
+		paymentData.ExpireDate = req.ExpireAt.In(kst).Format(reqDateLayout)
+	}
+
+	payLetterRes = utils.Post[ResTransactionNormalPay](
+		normalTransactionUrl,
+		paymentData,
+		http.Header{
+			"Authorization": []string{fmt.Sprintf("PLKEY %s", o.PaymentAPIKey)},
+			"Content-Type":  []string{"application/json"},
+		},
+	)
+
+	if payLetterRes.Code != nil {
+		err = errors.New(fmt.Sprintf("[%d]%s", *payLetterRes.Code, payLetterRes.Message))
+		return
+	}
+	return
+}
+
+func (o *MockPayLetter) RefundVirtualAccount(req ReqRefundVirtualAccount) (res ResCancelTransaction, err error) {
+	req.PgCode = PgCodeVirtualAccount
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	if o.Success {
+		res.TID = req.TID
+	} else {
+		err = errors.New("fake mock pay letter")
+	}
+	return
+}
+
+func (o *MockPayLetter) IssueCashReceipt(req ReqIssueCashReceipt) (res ResCashReceipt, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+	if err = checkOperation(Operation.IssueCashReceipt, req.PgCode); err != nil {
+		return
+	}
+
+	if o.Success {
+		res = ResCashReceipt{
+			TID:         req.TID,
+			ReceiptType: req.ReceiptType,
+			Amount:      req.Amount,
+			Status:      "issued",
+			IssueDate:   o.now().Format(payletterTimeLayouts[0]),
+		}
+	} else {
+		err = errors.New("fake mock pay letter")
+	}
+	return
+}
+
+func (o *MockPayLetter) ReissueCashReceipt(req ReqIssueCashReceipt) (res ResCashReceipt, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+	if err = checkOperation(Operation.ReissueCashReceipt, req.PgCode); err != nil {
+		return
+	}
+
+	return o.IssueCashReceipt(req)
+}
+
+func (o *MockPayLetter) CancelCashReceipt(req ReqCancelCashReceipt) (res ResCashReceipt, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	if o.Success {
+		res = ResCashReceipt{
+			TID:        req.TID,
+			Status:     "cancelled",
+			CancelDate: o.now().Format(payletterTimeLayouts[0]),
+		}
+	} else {
+		err = errors.New("fake mock pay letter")
+	}
+	return
+}
+
+func (o *MockPayLetter) GetCashReceipt(req ReqGetCashReceipt) (res ResCashReceipt, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	if o.Success {
+		res = ResCashReceipt{
+			TID:    req.TID,
+			Status: "issued",
+		}
+	} else {
+		err = errors.New("fake mock pay letter")
+	}
+	return
+}
+
+func (o *MockPayLetter) GetTransaction(req ReqGetTransaction) (res ResGetTransaction, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	if o.Success {
+		res.PgCode = req.PgCode
+		res.TID = req.TID
+		res.OrderNo = req.OrderNo
+		res.TransactionDate = o.now().Format(payletterTimeLayouts[0])
+	} else {
+		err = errors.New("fake mock pay letter")
+	}
+	return
+}
+
+func (o *MockPayLetter) DeleteEasyPayMethod(req ReqDeleteEasyPayMethod) (payLetterRes ResUpdateEasyPayMethod, err error) {
+	req.setReqDate(o.reqDate())
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	req.setClientID(o.ClientID)
+	req.setHashData(o.Signer())
+
+	payLetterRes = utils.Post[ResUpdateEasyPayMethod](
+		easyPayDeleteMethodTestUrl,
+		req,
+		http.Header{
+			"Authorization": []string{fmt.Sprintf("PLKEY %s", o.PaymentAPIKey)},
+			"Content-Type":  []string{"application/json"},
+		},
+	)
+	if payLetterRes.Code != nil {
+		err = errors.New(fmt.Sprintf("[%d]%s", *payLetterRes.Code, payLetterRes.Message))
+		return
+	}
+
+	return
+}
+
+func (o *MockPayLetter) RenameEasyPayMethod(req ReqRenameEasyPayMethod) (payLetterRes ResUpdateEasyPayMethod, err error) {
+	req.setReqDate(o.reqDate())
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	req.setClientID(o.ClientID)
+	req.setHashData(o.Signer())
+
+	payLetterRes = utils.Post[ResUpdateEasyPayMethod](
+		easyPayRenameMethodTestUrl,
+		req,
+		http.Header{
+			"Authorization": []string{fmt.Sprintf("PLKEY %s", o.PaymentAPIKey)},
+			"Content-Type":  []string{"application/json"},
+		},
+	)
+	if payLetterRes.Code != nil {
+		err = errors.New(fmt.Sprintf("[%d]%s", *payLetterRes.Code, payLetterRes.Message))
+		return
+	}
+
+	return
+}
+
+func (o *MockPayLetter) SetFavoriteEasyPayMethod(req ReqSetFavoriteEasyPayMethod) (payLetterRes ResUpdateEasyPayMethod, err error) {
+	req.setReqDate(o.reqDate())
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	req.setClientID(o.ClientID)
+	req.setHashData(o.Signer())
+
+	payLetterRes = utils.Post[ResUpdateEasyPayMethod](
+		easyPayFavoriteMethodTestUrl,
+		req,
+		http.Header{
+			"Authorization": []string{fmt.Sprintf("PLKEY %s", o.PaymentAPIKey)},
+			"Content-Type":  []string{"application/json"},
+		},
+	)
+	if payLetterRes.Code != nil {
+		err = errors.New(fmt.Sprintf("[%d]%s", *payLetterRes.Code, payLetterRes.Message))
+		return
+	}
+
+	return
+}
+
+func (o *MockPayLetter) UnregisterEasyPay(req ReqUnregisterEasyPay) (payLetterRes ResUpdateEasyPayMethod, err error) {
+	req.setReqDate(o.reqDate())
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	req.setClientID(o.ClientID)
+	req.setHashData(o.Signer())
+
+	payLetterRes = utils.Post[ResUpdateEasyPayMethod](
+		easyPayUnregisterTestUrl,
+		req,
+		http.Header{
+			"Authorization": []string{fmt.Sprintf("PLKEY %s", o.PaymentAPIKey)},
+			"Content-Type":  []string{"application/json"},
+		},
+	)
+	if payLetterRes.Code != nil {
+		err = errors.New(fmt.Sprintf("[%d]%s", *payLetterRes.Code, payLetterRes.Message))
+		return
+	}
+
+	return
+}
+
+func (o *MockPayLetter) ResetEasyPayPassword(req ReqEasyPayPassword) (payLetterRes ResEasyPayUI, err error) {
+	req.setReqDate(o.reqDate())
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	req.setClientID(o.ClientID)
+	req.setHashData(o.Signer())
+
+	payLetterRes = utils.Post[ResEasyPayUI](
+		easyPayResetPasswordTestUrl,
+		req,
+		http.Header{
+			"Authorization": []string{fmt.Sprintf("PLKEY %s", o.PaymentAPIKey)},
+			"Content-Type":  []string{"application/json"},
+		},
+	)
+
+	if payLetterRes.Code != nil {
+		// 에러 발생
+		err = errors.New(fmt.Sprintf("[%d]%s", *payLetterRes.Code, payLetterRes.Message))
+		return
+	}
+	return
+}
+
+func (o *MockPayLetter) ChangeEasyPayPassword(req ReqEasyPayPassword) (payLetterRes ResEasyPayUI, err error) {
+	req.setReqDate(o.reqDate())
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	req.setClientID(o.ClientID)
+	req.setHashData(o.Signer())
+
+	payLetterRes = utils.Post[ResEasyPayUI](
+		easyPayChangePasswordTestUrl,
+		req,
+		http.Header{
+			"Authorization": []string{fmt.Sprintf("PLKEY %s", o.PaymentAPIKey)},
+			"Content-Type":  []string{"application/json"},
+		},
+	)
+
+	if payLetterRes.Code != nil {
+		// 에러 발생
+		err = errors.New(fmt.Sprintf("[%d]%s", *payLetterRes.Code, payLetterRes.Message))
+		return
+	}
+	return
+}
+
+func (o *MockPayLetter) SetEasyPayPasswordSkip(req ReqSetEasyPayPasswordSkip) (payLetterRes ResEasyPayUI, err error) {
+	req.setReqDate(o.reqDate())
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	req.setClientID(o.ClientID)
+	req.setHashData(o.Signer())
+
+	payLetterRes = utils.Post[ResEasyPayUI](
+		easyPayPasswordSkipTestUrl,
+		req,
+		http.Header{
+			"Authorization": []string{fmt.Sprintf("PLKEY %s", o.PaymentAPIKey)},
+			"Content-Type":  []string{"application/json"},
+		},
+	)
+
+	if payLetterRes.Code != nil {
+		// 에러 발생
+		err = errors.New(fmt.Sprintf("[%d]%s", *payLetterRes.Code, payLetterRes.Message))
+		return
+	}
+	return
+}
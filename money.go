@@ -0,0 +1,86 @@
+package payletter
+
+import (
+	"strconv"
+)
+
+// KRW 원화 금액
+type KRW int
+
+func (o KRW) Int() int {
+	return int(o)
+}
+
+// String 천 단위 구분 기호를 넣은 금액 (1,000원)
+func (o KRW) String() string {
+	s := strconv.Itoa(int(o))
+	sign := ""
+	if o < 0 {
+		sign, s = "-", s[1:]
+	}
+
+	b := make([]byte, 0, len(s)+len(s)/3)
+	for i := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b = append(b, ',')
+		}
+		b = append(b, s[i])
+	}
+
+	return sign + string(b) + "원"
+}
+
+// VAT 부가세 포함 금액의 부가세 (1/11, 원 미만 절사)
+func (o KRW) VAT() KRW {
+	return o / 11
+}
+
+// SupplyValue 부가세 포함 금액의 공급가액
+func (o KRW) SupplyValue() KRW {
+	return o - o.VAT()
+}
+
+// AmountBreakdown 결제 금액 구성, 총액 = 공급가액 + 부가세 + 면세 금액 + 봉사료
+type AmountBreakdown struct {
+	TaxAmount     KRW `json:"tax_amount,omitempty"`     // 부가세
+	TaxFreeAmount KRW `json:"taxfree_amount,omitempty"` // 면세 금액
+	ServiceAmount KRW `json:"service_amount,omitempty"` // 봉사료
+}
+
+// NewAmountBreakdown 총액에서 면세 금액과 봉사료를 뺀 과세 금액으로 부가세 계산
+func NewAmountBreakdown(total, taxFree, service KRW) AmountBreakdown {
+	return AmountBreakdown{
+		TaxAmount:     (total - taxFree - service).VAT(),
+		TaxFreeAmount: taxFree,
+		ServiceAmount: service,
+	}
+}
+
+func (o AmountBreakdown) IsZero() bool {
+	return o == AmountBreakdown{}
+}
+
+// SupplyAmount 총액에서 부가세, 면세 금액, 봉사료를 뺀 공급가액
+func (o AmountBreakdown) SupplyAmount(total KRW) KRW {
+	return total - o.TaxAmount - o.TaxFreeAmount - o.ServiceAmount
+}
+
+func (o AmountBreakdown) rules(total int) []*FieldError {
+	if o.IsZero() {
+		return nil
+	}
+
+	r := rules(
+		amountRange("tax_amount", int(o.TaxAmount), 0),
+		amountRange("taxfree_amount", int(o.TaxFreeAmount), 0),
+		amountRange("service_amount", int(o.ServiceAmount), 0),
+	)
+	// 과세 금액 = 총액 - 면세 금액 - 봉사료 = 공급가액 + 부가세, 부가세는 과세 금액의 1/11 (원 미만 절사 또는 올림)
+	taxable := KRW(total) - o.TaxFreeAmount - o.ServiceAmount
+	if taxable < 0 {
+		r = append(r, fieldError("amount", "면세 금액, 봉사료 합계가 총액 %s 보다 큼", KRW(total)))
+	} else if vat := taxable.VAT(); o.TaxAmount != vat && o.TaxAmount != (taxable+10)/11 {
+		r = append(r, fieldError("tax_amount", "부가세 %s 가 과세 금액 %s 의 부가세 %s 와 다름", o.TaxAmount, taxable, vat))
+	}
+	return r
+}
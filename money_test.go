@@ -0,0 +1,40 @@
+package payletter
+
+import "testing"
+
+func TestKRWString(t *testing.T) {
+	for amount, want := range map[KRW]string{0: "0원", 999: "999원", 1000: "1,000원", 1234567: "1,234,567원", -1000: "-1,000원"} {
+		if got := amount.String(); got != want {
+			t.Errorf("KRW(%d).String() = %s, want %s", int(amount), got, want)
+		}
+	}
+}
+
+func TestAmountBreakdownRules(t *testing.T) {
+	tests := []struct {
+		name      string
+		total     int
+		breakdown AmountBreakdown
+		valid     bool
+	}{
+		{"zero", 11000, AmountBreakdown{}, true},
+		{"vat", 11000, NewAmountBreakdown(11000, 0, 0), true},
+		{"vat with taxfree and service", 15000, NewAmountBreakdown(15000, 2000, 1000), true},
+		{"vat rounded up", 1005, AmountBreakdown{TaxAmount: 92}, true},
+		{"vat rounded down", 1005, AmountBreakdown{TaxAmount: 91}, true},
+		{"all taxfree", 5000, AmountBreakdown{TaxFreeAmount: 5000}, true},
+		{"vat too small", 11000, AmountBreakdown{TaxAmount: 500}, false},
+		{"vat too large", 11000, AmountBreakdown{TaxAmount: 1500}, false},
+		{"missing vat", 11000, AmountBreakdown{TaxFreeAmount: 1000}, false},
+		{"parts exceed total", 1000, AmountBreakdown{TaxFreeAmount: 800, ServiceAmount: 300}, false},
+		{"negative", 1000, AmountBreakdown{TaxAmount: -10}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validate(tt.breakdown.rules(tt.total))
+			if (err == nil) != tt.valid {
+				t.Fatalf("err = %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
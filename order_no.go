@@ -0,0 +1,118 @@
+package payletter
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	MaxOrderNoLength = 50 // 페이레터 order_no 최대 길이
+
+	orderNoTimeLayout = "20060102150405.000"
+	orderNoTimeLength = 17 // yyyyMMddHHmmssSSS
+	orderNoSeqLength  = 3
+	orderNoRandLength = 8
+	orderNoBodyLength = orderNoTimeLength + orderNoSeqLength + orderNoRandLength
+	orderNoAlphabet   = "0123456789ABCDEFGHJKMNPQRSTVWXYZ" // Crockford base32
+)
+
+var ErrInvalidOrderNo = errors.New("유효하지 않은 주문번호")
+
+// ValidateOrderNo 페이레터가 허용하는 길이, 문자(영문, 숫자, -, _)인지 검사
+func ValidateOrderNo(orderNo string) error {
+	if orderNo == "" {
+		return fmt.Errorf("%w: 비어 있음", ErrInvalidOrderNo)
+	}
+	if len(orderNo) > MaxOrderNoLength {
+		return fmt.Errorf("%w: 길이 %d > %d", ErrInvalidOrderNo, len(orderNo), MaxOrderNoLength)
+	}
+	for _, c := range orderNo {
+		if !isOrderNoChar(c) {
+			return fmt.Errorf("%w: 허용되지 않은 문자 %q", ErrInvalidOrderNo, c)
+		}
+	}
+	return nil
+}
+
+func isOrderNoChar(c rune) bool {
+	switch {
+	case c >= '0' && c <= '9', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '-', c == '_':
+		return true
+	default:
+		return false
+	}
+}
+
+// OrderNoGenerator 시간순 정렬되는 주문번호 생성기
+//
+// 형식: prefix + KST yyyyMMddHHmmssSSS + 같은 ms 내 순번(base32 3자리) + 난수(base32 8자리)
+type OrderNoGenerator struct {
+	prefix string
+	now    Clock
+
+	mu         sync.Mutex
+	lastMillis int64
+	seq        int
+}
+
+func NewOrderNoGenerator(prefix string, opts ...Option) (*OrderNoGenerator, error) {
+	if len(prefix) > MaxOrderNoLength-orderNoBodyLength {
+		return nil, fmt.Errorf("%w: prefix 길이 %d > %d", ErrInvalidOrderNo, len(prefix), MaxOrderNoLength-orderNoBodyLength)
+	}
+	for _, c := range prefix {
+		if !isOrderNoChar(c) {
+			return nil, fmt.Errorf("%w: prefix 에 허용되지 않은 문자 %q", ErrInvalidOrderNo, c)
+		}
+	}
+
+	return &OrderNoGenerator{
+		prefix: prefix,
+		now:    newOptions(opts).clock,
+	}, nil
+}
+
+func (o *OrderNoGenerator) Generate() string {
+	o.mu.Lock()
+	now := o.now().In(kst)
+	millis := now.UnixMilli()
+	if millis > o.lastMillis {
+		o.lastMillis = millis
+		o.seq = 0
+	} else {
+		// 같은 ms 이거나 시계가 뒤로 간 경우 마지막 시각 기준으로 순번 증가
+		o.seq++
+		if o.seq >= len(orderNoAlphabet)*len(orderNoAlphabet)*len(orderNoAlphabet) {
+			o.lastMillis++
+			o.seq = 0
+		}
+		now = time.UnixMilli(o.lastMillis).In(kst)
+	}
+	seq := o.seq
+	o.mu.Unlock()
+
+	b := make([]byte, 0, len(o.prefix)+orderNoBodyLength)
+	b = append(b, o.prefix...)
+	for _, c := range now.Format(orderNoTimeLayout) {
+		if c != '.' {
+			b = append(b, byte(c))
+		}
+	}
+	b = append(b,
+		orderNoAlphabet[seq/(32*32)],
+		orderNoAlphabet[seq/32%32],
+		orderNoAlphabet[seq%32],
+	)
+
+	random := make([]byte, orderNoRandLength)
+	if _, err := rand.Read(random); err != nil {
+		panic(err)
+	}
+	for _, r := range random {
+		b = append(b, orderNoAlphabet[r%32])
+	}
+
+	return string(b)
+}
@@ -0,0 +1,335 @@
+package payletter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+var ErrPendingOrderNotFound = errors.New("pending order not found")
+
+const (
+	defaultSweepTimeout   = 30 * time.Minute
+	defaultSweepBatchSize = 100
+)
+
+// PendingOrder 결제창 URL 을 발급했지만 결제 callback 처리가 끝나지 않은 주문
+type PendingOrder struct {
+	OrderNo        string    `json:"order_no"`
+	PgCode         PgCode    `json:"pgcode"`
+	UserID         int64     `json:"user_id"`
+	Amount         int       `json:"amount"`
+	EasyPay        bool      `json:"easy_pay"`        // TransactionEasyPay 주문, CancelEasyPay 로 취소
+	RefundRequired bool      `json:"refund_required"` // 결제 후 후속 처리 실패, 대기 시간 없이 취소
+	FailReason     string    `json:"fail_reason"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type IPendingOrderStore interface {
+	Add(order PendingOrder) error
+	// Remove callback 처리 완료 또는 sweep 완료, 없으면 ErrPendingOrderNotFound
+	Remove(orderNo string) error
+	// MarkRefundRequired 결제는 되었지만 후속 처리에 실패한 주문, 없으면 ErrPendingOrderNotFound
+	MarkRefundRequired(orderNo, reason string) error
+	// ListSweepable before 이전에 생성된 주문과 RefundRequired 주문 최대 limit 개, 생성 순
+	ListSweepable(before time.Time, limit int) ([]PendingOrder, error)
+}
+
+// NewPendingOrder TransactionNormalPay 로 결제창 URL 을 발급한 주문
+func NewPendingOrder(req ReqTransactionNormalPay, createdAt time.Time) PendingOrder {
+	return PendingOrder{
+		OrderNo:   req.OrderNo,
+		PgCode:    req.PgCode,
+		UserID:    int64(req.UserID),
+		Amount:    req.Amount,
+		CreatedAt: createdAt,
+	}
+}
+
+// NewPendingEasyPayOrder TransactionEasyPay 로 결제 요청한 주문
+func NewPendingEasyPayOrder(req ReqTransactionEasyPay, createdAt time.Time) PendingOrder {
+	return PendingOrder{
+		OrderNo:   req.OrderNo,
+		PgCode:    req.PgCode,
+		UserID:    int64(req.UserID),
+		Amount:    req.Amount,
+		EasyPay:   true,
+		CreatedAt: createdAt,
+	}
+}
+
+type SweepResult struct {
+	Order   PendingOrder `json:"order"`
+	Outcome string       `json:"outcome"`
+	TID     string       `json:"tid"`
+	Amount  int          `json:"amount"` // 취소 금액
+	Err     error        `json:"-"`
+}
+
+// OrderSweeper callback 이 오지 않은 주문과 후속 처리에 실패한 주문을 정리
+//
+// 대기 시간이 지난 주문을 거래 목록에서 찾아 결제되지 않았으면 삭제하고, 결제되었으면 취소
+type OrderSweeper struct {
+	PayLetter IPayLetter
+	Store     IPendingOrderStore
+	Timeout   time.Duration // callback 대기 시간, 0 이면 30분
+	BatchSize int           // 한번에 처리할 주문 수, 0 이면 100
+	Interval  time.Duration // Run 의 실행 간격, 0 이면 Timeout
+
+	// 네이버페이 주문 조회, 취소용
+	NaverAPIClientID  string
+	NaverAPIKey       string
+	NaverAPISearchKey string
+
+	OnResult func(result SweepResult) // 주문 처리 결과 (로그, metrics 용)
+	OnError  func(err error)          // Run 에서 SweepOnce 가 실패했을 때 호출 (주문 목록 조회 실패 등), 다음 주기에 재시도
+	now      Clock
+}
+
+func NewOrderSweeper(p IPayLetter, store IPendingOrderStore, opts ...Option) *OrderSweeper {
+	return &OrderSweeper{
+		PayLetter: p,
+		Store:     store,
+		now:       newOptions(opts).clock,
+	}
+}
+
+// SweepOnce 정리 대상 주문을 한 batch 처리
+func (o *OrderSweeper) SweepOnce(ctx context.Context) (results []SweepResult, err error) {
+	timeout := o.Timeout
+	if timeout <= 0 {
+		timeout = defaultSweepTimeout
+	}
+	batchSize := o.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultSweepBatchSize
+	}
+
+	orders, err := o.Store.ListSweepable(o.now().Add(-timeout), batchSize)
+	if err != nil {
+		return
+	}
+
+	lists := make(map[string][]Transaction) // pgcode/date 별 거래 목록, 이번 sweep 동안만 사용
+	for _, order := range orders {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		result := o.sweep(order, lists)
+		if result.Outcome != SweepOutcome.Failed {
+			if removeErr := o.Store.Remove(order.OrderNo); removeErr != nil && !errors.Is(removeErr, ErrPendingOrderNotFound) {
+				result.Err = removeErr
+			}
+		}
+
+		results = append(results, result)
+		if o.OnResult != nil {
+			o.OnResult(result)
+		}
+	}
+	return
+}
+
+func (o *OrderSweeper) Run(ctx context.Context) error {
+	interval := o.Interval
+	if interval <= 0 {
+		interval = o.Timeout
+	}
+	if interval <= 0 {
+		interval = defaultSweepTimeout
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// 실패한 주문은 다음 주기에 재시도
+		if _, err := o.SweepOnce(ctx); err != nil && ctx.Err() == nil && o.OnError != nil {
+			o.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (o *OrderSweeper) sweep(order PendingOrder, lists map[string][]Transaction) (result SweepResult) {
+	result.Order = order
+
+	rows, err := o.findTransactions(order, lists)
+	if err != nil {
+		result.Outcome = SweepOutcome.Failed
+		result.Err = err
+		return
+	}
+
+	if len(rows) == 0 {
+		if order.RefundRequired {
+			// 결제 후 실패로 표시했는데 거래가 없음, 목록 반영 지연일 수 있어 다음 sweep 에서 재확인
+			result.Outcome = SweepOutcome.Failed
+			result.Err = fmt.Errorf("%w: [%s]환불 대상 거래", ErrTransactionNotFound, order.OrderNo)
+			return
+		}
+		result.Outcome = SweepOutcome.Abandoned
+		return
+	}
+
+	transaction := newResGetTransaction(rows)
+	result.TID = transaction.TID
+	if transaction.Incomplete {
+		// 취소 건만 찾아 남은 금액을 알 수 없음, 다음 sweep 에서 재확인
+		result.Outcome = SweepOutcome.Failed
+		result.Err = fmt.Errorf("[%s]원 거래 없이 취소 건만 조회됨", order.OrderNo)
+		return
+	}
+	if transaction.IsCancelled() {
+		result.Outcome = SweepOutcome.AlreadyCancelled
+		return
+	}
+
+	result.Amount = transaction.RemainingAmount()
+	if err = o.cancel(order, transaction); err != nil {
+		result.Outcome = SweepOutcome.Failed
+		result.Err = err
+		return
+	}
+	result.Outcome = SweepOutcome.Cancelled
+	return
+}
+
+// findTransactions 주문 생성일, 다음 날 거래 목록에서 주문번호로 찾음 (자정 직전 주문)
+func (o *OrderSweeper) findTransactions(order PendingOrder, lists map[string][]Transaction) (rows []Transaction, err error) {
+	today := o.now().In(kst).Format(transactionDateLayout) // 거래 목록 일자는 KST 기준
+	created := order.CreatedAt.In(kst)
+
+	for _, day := range []time.Time{created, created.AddDate(0, 0, 1)} {
+		date := day.Format(transactionDateLayout)
+		if date > today {
+			break
+		}
+
+		key := order.PgCode.String() + "/" + date
+		list, cached := lists[key]
+		if !cached {
+			var res ResGetTransactionList
+			res, err = o.PayLetter.GetTransactionList(ReqGetTransactionList{
+				Date:              date,
+				DateType:          TransactionDateType.Transaction,
+				PgCode:            order.PgCode,
+				NaverAPIClientID:  o.NaverAPIClientID,
+				NaverAPISearchKey: o.NaverAPISearchKey,
+			})
+			if err != nil {
+				return
+			}
+			list = res.List
+			lists[key] = list
+		}
+
+		rows = append(rows, findTransactions(list, "", order.OrderNo)...)
+	}
+	return
+}
+
+func (o *OrderSweeper) cancel(order PendingOrder, transaction ResGetTransaction) (err error) {
+	if order.EasyPay {
+		_, err = o.PayLetter.CancelEasyPay(ReqCancelEasyPay{
+			UserID: int(order.UserID),
+			Tid:    transaction.TID,
+			Amount: transaction.RemainingAmount(),
+		})
+		return
+	}
+
+	if transaction.CancelledAmount > 0 {
+		// 부분 취소된 거래는 전체 취소할 수 없으므로 남은 금액을 부분 취소
+		_, err = o.PayLetter.PartialCancelTransaction(ReqPartialCancelTransaction{
+			PgCode:           order.PgCode,
+			UserID:           order.UserID,
+			TID:              transaction.TID,
+			Amount:           transaction.RemainingAmount(),
+			NaverAPIClientId: o.NaverAPIClientID,
+			NaverAPIKey:      o.NaverAPIKey,
+		})
+		return
+	}
+
+	_, err = o.PayLetter.CancelTransaction(ReqCancelTransaction{
+		PgCode:           order.PgCode,
+		UserID:           order.UserID,
+		TID:              transaction.TID,
+		NaverAPIClientId: o.NaverAPIClientID,
+		NaverAPIKey:      o.NaverAPIKey,
+	})
+	return
+}
+
+type MemoryPendingOrderStore struct {
+	mu     sync.RWMutex
+	orders map[string]PendingOrder
+}
+
+func NewMemoryPendingOrderStore() *MemoryPendingOrderStore {
+	return &MemoryPendingOrderStore{
+		orders: make(map[string]PendingOrder),
+	}
+}
+
+func (o *MemoryPendingOrderStore) Add(order PendingOrder) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.orders[order.OrderNo] = order
+	return nil
+}
+
+func (o *MemoryPendingOrderStore) Remove(orderNo string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, exists := o.orders[orderNo]; !exists {
+		return ErrPendingOrderNotFound
+	}
+	delete(o.orders, orderNo)
+	return nil
+}
+
+func (o *MemoryPendingOrderStore) MarkRefundRequired(orderNo, reason string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	order, exists := o.orders[orderNo]
+	if !exists {
+		return ErrPendingOrderNotFound
+	}
+	order.RefundRequired = true
+	order.FailReason = reason
+	o.orders[orderNo] = order
+	return nil
+}
+
+func (o *MemoryPendingOrderStore) ListSweepable(before time.Time, limit int) ([]PendingOrder, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	orders := make([]PendingOrder, 0)
+	for _, order := range o.orders {
+		if order.RefundRequired || order.CreatedAt.Before(before) {
+			orders = append(orders, order)
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].CreatedAt.Before(orders[j].CreatedAt)
+	})
+	if limit > 0 && len(orders) > limit {
+		orders = orders[:limit]
+	}
+	return orders, nil
+}
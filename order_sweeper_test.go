@@ -0,0 +1,162 @@
+package payletter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOrderSweeperUsesKSTDate(t *testing.T) {
+	// KST 2024-03-02 00:30 = UTC 2024-03-01 15:30
+	clock := newFixedClock(time.Date(2024, 3, 1, 15, 30, 0, 0, time.UTC))
+	created := time.Date(2024, 3, 1, 23, 50, 0, 0, kst)
+
+	var dates []string
+	p := &fakePayLetter{
+		getTransactionList: func(req ReqGetTransactionList) (ResGetTransactionList, error) {
+			dates = append(dates, req.Date)
+			if req.Date != "20240302" {
+				return ResGetTransactionList{}, nil
+			}
+			return ResGetTransactionList{List: []Transaction{
+				{PgCode: PgCodeCreditCard, TID: "tid-1", OrderNo: "order-1", Amount: 1000, TransactionDate: "2024-03-02 00:01:00"},
+			}}, nil
+		},
+		cancelTransaction: func(req ReqCancelTransaction) (ResCancelTransaction, error) {
+			return ResCancelTransaction{TID: req.TID}, nil
+		},
+	}
+
+	store := NewMemoryPendingOrderStore()
+	_ = store.Add(PendingOrder{OrderNo: "order-1", PgCode: PgCodeCreditCard, UserID: 1, Amount: 1000, CreatedAt: created})
+
+	sweeper := NewOrderSweeper(p, store, WithClock(clock.Now))
+	sweeper.Timeout = 10 * time.Minute
+
+	results, err := sweeper.SweepOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dates) != 2 || dates[0] != "20240301" || dates[1] != "20240302" {
+		t.Fatalf("조회 일자 %v, want [20240301 20240302]", dates)
+	}
+	if len(results) != 1 || results[0].Outcome != SweepOutcome.Cancelled || results[0].TID != "tid-1" {
+		t.Fatalf("results %+v", results)
+	}
+	if p.count("CancelTransaction") != 1 {
+		t.Fatalf("CancelTransaction %d 회 호출", p.count("CancelTransaction"))
+	}
+}
+
+func TestOrderSweeperAbandoned(t *testing.T) {
+	clock := newFixedClock(time.Date(2024, 3, 1, 12, 0, 0, 0, kst))
+	p := &fakePayLetter{
+		getTransactionList: func(req ReqGetTransactionList) (ResGetTransactionList, error) {
+			return ResGetTransactionList{}, nil
+		},
+	}
+
+	store := NewMemoryPendingOrderStore()
+	_ = store.Add(PendingOrder{OrderNo: "order-1", PgCode: PgCodeCreditCard, CreatedAt: clock.Now().Add(-time.Hour)})
+	_ = store.Add(PendingOrder{OrderNo: "order-2", PgCode: PgCodeCreditCard, CreatedAt: clock.Now().Add(-time.Minute)})
+
+	results, err := NewOrderSweeper(p, store, WithClock(clock.Now)).SweepOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Order.OrderNo != "order-1" || results[0].Outcome != SweepOutcome.Abandoned {
+		t.Fatalf("results %+v", results)
+	}
+	if err = store.Remove("order-2"); err != nil {
+		t.Fatalf("대기 시간이 지나지 않은 주문이 삭제됨: %v", err)
+	}
+}
+
+func TestOrderSweeperCancelsRemainingAmountOfPartialCancel(t *testing.T) {
+	clock := newFixedClock(time.Date(2024, 3, 1, 12, 0, 0, 0, kst))
+	p := &fakePayLetter{
+		getTransactionList: func(req ReqGetTransactionList) (ResGetTransactionList, error) {
+			return ResGetTransactionList{List: []Transaction{
+				{PgCode: PgCodeCreditCard, TID: "tid-1", OrderNo: "order-1", Amount: 1000, TransactionDate: "2024-03-01 11:00:00"},
+				{PgCode: PgCodeCreditCard, TID: "tid-1", OrderNo: "order-1", Amount: 300, CancelDate: "2024-03-01 11:10:00"},
+			}}, nil
+		},
+	}
+	var cancelled []ReqPartialCancelTransaction
+	p.partialCancel = func(req ReqPartialCancelTransaction) (ResPartialCancelTransaction, error) {
+		cancelled = append(cancelled, req)
+		return ResPartialCancelTransaction{}, nil
+	}
+
+	store := NewMemoryPendingOrderStore()
+	_ = store.Add(PendingOrder{OrderNo: "order-1", PgCode: PgCodeCreditCard, UserID: 1, Amount: 1000, RefundRequired: true, CreatedAt: clock.Now().Add(-time.Hour)})
+
+	results, err := NewOrderSweeper(p, store, WithClock(clock.Now)).SweepOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Outcome != SweepOutcome.Cancelled || results[0].Amount != 700 {
+		t.Fatalf("results %+v", results)
+	}
+	if len(cancelled) != 1 || cancelled[0].Amount != 700 || cancelled[0].TID != "tid-1" {
+		t.Fatalf("부분 취소 요청 %+v", cancelled)
+	}
+	if p.count("CancelTransaction") != 0 {
+		t.Fatal("부분 취소된 거래를 전체 취소함")
+	}
+}
+
+// failingPendingOrderStore 주문 목록 조회만 실패하는 저장소
+type failingPendingOrderStore struct {
+	*MemoryPendingOrderStore
+}
+
+func (o failingPendingOrderStore) ListSweepable(before time.Time, limit int) ([]PendingOrder, error) {
+	return nil, errors.New("db down")
+}
+
+func TestOrderSweeperRunReportsErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sweeper := NewOrderSweeper(&fakePayLetter{}, failingPendingOrderStore{NewMemoryPendingOrderStore()})
+	var reported error
+	sweeper.OnError = func(err error) {
+		reported = err
+		cancel()
+	}
+
+	if err := sweeper.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run err = %v", err)
+	}
+	if reported == nil {
+		t.Fatal("SweepOnce 에러가 OnError 로 전달되지 않음")
+	}
+}
+
+func TestOrderSweeperDoesNotGuessAmountFromCancelRows(t *testing.T) {
+	clock := newFixedClock(time.Date(2024, 3, 1, 12, 0, 0, 0, kst))
+	p := &fakePayLetter{
+		getTransactionList: func(req ReqGetTransactionList) (ResGetTransactionList, error) {
+			// 원 거래는 조회 일자 밖, 부분 취소 건만 있음
+			return ResGetTransactionList{List: []Transaction{
+				{PgCode: PgCodeCreditCard, TID: "tid-1", OrderNo: "order-1", Amount: 300, CancelDate: "2024-03-01 11:10:00"},
+			}}, nil
+		},
+	}
+
+	store := NewMemoryPendingOrderStore()
+	_ = store.Add(PendingOrder{OrderNo: "order-1", PgCode: PgCodeCreditCard, UserID: 1, Amount: 1000, RefundRequired: true, CreatedAt: clock.Now().Add(-time.Hour)})
+
+	results, err := NewOrderSweeper(p, store, WithClock(clock.Now)).SweepOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Outcome != SweepOutcome.Failed {
+		t.Fatalf("취소 건만으로 전체 취소 판단: results %+v", results)
+	}
+	if err = store.Remove("order-1"); err != nil {
+		t.Fatalf("환불 대상 주문이 삭제됨: %v", err)
+	}
+}
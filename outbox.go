@@ -0,0 +1,314 @@
+package payletter
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SQLExecer *sql.DB, *sql.Tx 공통, 결제 상태 변경과 같은 트랜잭션에서 outbox 에 저장할 때 사용
+type SQLExecer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// IOutboxStore 발행 대기 이벤트 저장소
+//
+// Add 는 결제 상태 변경과 같은 DB 트랜잭션 안에서 호출해야 커밋과 발행 사이에 프로세스가 죽어도 이벤트가 유실되지 않음
+type IOutboxStore interface {
+	// Add 이벤트 저장, tx 가 nil 이 아니면 해당 트랜잭션으로 저장 (DB 를 쓰지 않는 저장소는 무시)
+	Add(tx SQLExecer, events ...PaymentEvent) error
+	// Pending 발행되지 않은 이벤트를 발생 순서대로 최대 limit 개 조회
+	Pending(limit int) ([]PaymentEvent, error)
+	// MarkPublished 발행 완료 처리
+	MarkPublished(ids ...string) error
+}
+
+type IEventPublisher interface {
+	Publish(ctx context.Context, event PaymentEvent) error
+}
+
+// OutboxRelay outbox 에 쌓인 이벤트를 publisher 로 발행 (at-least-once)
+type OutboxRelay struct {
+	Store     IOutboxStore
+	Publisher IEventPublisher
+	BatchSize int                                 // 한번에 조회할 이벤트 수, 0 이면 100
+	Interval  time.Duration                       // Run 의 polling 간격, 0 이면 1초
+	OnError   func(event PaymentEvent, err error) // 발행 실패 시 호출 (로그, metrics 용)
+}
+
+// RelayOnce 대기 중인 이벤트를 한 batch 발행, 순서 보장을 위해 발행 실패 시 그 이후 이벤트는 다음 시도로 미룸
+func (o *OutboxRelay) RelayOnce(ctx context.Context) (published int, err error) {
+	batchSize := o.BatchSize
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	events, err := o.Store.Pending(batchSize)
+	if err != nil {
+		return
+	}
+
+	for _, event := range events {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		if err = o.Publisher.Publish(ctx, event); err != nil {
+			if o.OnError != nil {
+				o.OnError(event, err)
+			}
+			return
+		}
+
+		// 발행 후 표시 전에 죽으면 재발행 되므로 소비자는 ID 로 중복 제거
+		if err = o.Store.MarkPublished(event.ID); err != nil {
+			return
+		}
+		published++
+	}
+
+	return
+}
+
+// Run ctx 가 끝날 때까지 주기적으로 RelayOnce 실행
+func (o *OutboxRelay) Run(ctx context.Context) error {
+	interval := o.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// 실패한 이벤트는 다음 주기에 재시도
+		_, _ = o.RelayOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+type outboxEntry struct {
+	seq       int
+	event     PaymentEvent
+	published bool
+}
+
+type MemoryOutboxStore struct {
+	mu      sync.Mutex
+	seq     int
+	entries map[string]*outboxEntry
+}
+
+func NewMemoryOutboxStore() *MemoryOutboxStore {
+	return &MemoryOutboxStore{
+		entries: make(map[string]*outboxEntry),
+	}
+}
+
+func (o *MemoryOutboxStore) Add(_ SQLExecer, events ...PaymentEvent) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, event := range events {
+		if _, exists := o.entries[event.ID]; exists {
+			continue
+		}
+		o.seq++
+		o.entries[event.ID] = &outboxEntry{seq: o.seq, event: event}
+	}
+	return nil
+}
+
+func (o *MemoryOutboxStore) Pending(limit int) ([]PaymentEvent, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	pending := make([]*outboxEntry, 0)
+	for _, entry := range o.entries {
+		if !entry.published {
+			pending = append(pending, entry)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].seq < pending[j].seq })
+
+	if limit > 0 && len(pending) > limit {
+		pending = pending[:limit]
+	}
+
+	events := make([]PaymentEvent, 0, len(pending))
+	for _, entry := range pending {
+		events = append(events, entry.event)
+	}
+	return events, nil
+}
+
+func (o *MemoryOutboxStore) MarkPublished(ids ...string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, id := range ids {
+		if entry, exists := o.entries[id]; exists {
+			entry.published = true
+		}
+	}
+	return nil
+}
+
+// OutboxTableDDL SQLOutboxStore 용 테이블 (MySQL 기준)
+const OutboxTableDDL = `CREATE TABLE payletter_outbox (
+	seq          BIGINT       NOT NULL AUTO_INCREMENT PRIMARY KEY,
+	id           CHAR(32)     NOT NULL UNIQUE,
+	type         VARCHAR(50)  NOT NULL,
+	order_no     VARCHAR(50)  NOT NULL,
+	tid          VARCHAR(100) NOT NULL,
+	user_id      BIGINT       NOT NULL,
+	payload      BLOB         NOT NULL,
+	occurred_at  BIGINT       NOT NULL,
+	published_at BIGINT       NOT NULL DEFAULT 0,
+	INDEX idx_payletter_outbox_pending (published_at, seq)
+)`
+
+// SQLOutboxStore database/sql 기반 저장소, placeholder 는 ? 를 사용 (MySQL, SQLite)
+type SQLOutboxStore struct {
+	DB    *sql.DB
+	Table string // 비어 있으면 payletter_outbox
+}
+
+func NewSQLOutboxStore(db *sql.DB) *SQLOutboxStore {
+	return &SQLOutboxStore{
+		DB: db,
+	}
+}
+
+func (o *SQLOutboxStore) table() string {
+	if o.Table == "" {
+		return "payletter_outbox"
+	}
+	return o.Table
+}
+
+func (o *SQLOutboxStore) Add(tx SQLExecer, events ...PaymentEvent) (err error) {
+	if tx == nil {
+		tx = o.DB
+	}
+
+	for _, event := range events {
+		_, err = tx.Exec(
+			fmt.Sprintf("INSERT INTO %s (id, type, order_no, tid, user_id, payload, occurred_at, published_at) VALUES (?, ?, ?, ?, ?, ?, ?, 0)", o.table()),
+			event.ID, event.Type, event.OrderNo, event.TID, event.UserID, []byte(event.Payload), event.OccurredAt.UnixMilli(),
+		)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (o *SQLOutboxStore) Pending(limit int) (events []PaymentEvent, err error) {
+	query := fmt.Sprintf("SELECT id, type, order_no, tid, user_id, payload, occurred_at FROM %s WHERE published_at = 0 ORDER BY seq", o.table())
+	args := make([]any, 0, 1)
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := o.DB.Query(query, args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event PaymentEvent
+		var payload []byte
+		var occurredAt int64
+		if err = rows.Scan(&event.ID, &event.Type, &event.OrderNo, &event.TID, &event.UserID, &payload, &occurredAt); err != nil {
+			return
+		}
+		event.Payload = payload
+		event.OccurredAt = time.UnixMilli(occurredAt)
+		events = append(events, event)
+	}
+	err = rows.Err()
+	return
+}
+
+func (o *SQLOutboxStore) MarkPublished(ids ...string) (err error) {
+	if len(ids) == 0 {
+		return
+	}
+
+	args := make([]any, 0, len(ids)+1)
+	args = append(args, time.Now().UnixMilli())
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	_, err = o.DB.Exec(
+		fmt.Sprintf("UPDATE %s SET published_at = ? WHERE id IN (%s)", o.table(), strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")),
+		args...,
+	)
+	return
+}
+
+// MemoryEventBroker 테스트용 in-memory publisher
+type MemoryEventBroker struct {
+	mu          sync.Mutex
+	events      []PaymentEvent
+	subscribers []func(PaymentEvent)
+	failWith    error
+}
+
+func NewMemoryEventBroker() *MemoryEventBroker {
+	return &MemoryEventBroker{}
+}
+
+func (o *MemoryEventBroker) Publish(_ context.Context, event PaymentEvent) error {
+	o.mu.Lock()
+	if o.failWith != nil {
+		err := o.failWith
+		o.mu.Unlock()
+		return err
+	}
+	o.events = append(o.events, event)
+	subscribers := append(make([]func(PaymentEvent), 0, len(o.subscribers)), o.subscribers...)
+	o.mu.Unlock()
+
+	for _, fn := range subscribers {
+		fn(event)
+	}
+	return nil
+}
+
+// Subscribe 이후 발행되는 이벤트를 받을 handler 등록
+func (o *MemoryEventBroker) Subscribe(fn func(PaymentEvent)) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.subscribers = append(o.subscribers, fn)
+}
+
+// FailWith nil 이 아니면 이후 모든 발행을 err 로 실패시킴
+func (o *MemoryEventBroker) FailWith(err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.failWith = err
+}
+
+// Events 지금까지 발행된 이벤트
+func (o *MemoryEventBroker) Events() []PaymentEvent {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return append([]PaymentEvent(nil), o.events...)
+}
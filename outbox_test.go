@@ -0,0 +1,103 @@
+package payletter
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func mustEvent(t *testing.T) func(PaymentEvent, error) PaymentEvent {
+	return func(event PaymentEvent, err error) PaymentEvent {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return event
+	}
+}
+
+func TestOutboxRelayPublishesInOrder(t *testing.T) {
+	must := mustEvent(t)
+	store := NewMemoryOutboxStore()
+	broker := NewMemoryEventBroker()
+
+	events := []PaymentEvent{
+		must(NewPaymentCancelledEvent("order-1", ReqCancelTransaction{PgCode: PgCodeCreditCard, UserID: 1}, ResCancelTransaction{TID: "tid-1"})),
+		must(NewPaymentCancelledEvent("order-2", ReqCancelTransaction{PgCode: PgCodeCreditCard, UserID: 1}, ResCancelTransaction{TID: "tid-2"})),
+		must(NewPaymentCancelledEvent("order-3", ReqCancelTransaction{PgCode: PgCodeCreditCard, UserID: 1}, ResCancelTransaction{TID: "tid-3"})),
+	}
+	var payload PaymentCancelledPayload
+	if err := events[0].Decode(&payload); err != nil || events[0].OrderNo != "order-1" || payload.OrderNo != "order-1" {
+		t.Fatalf("취소 이벤트 주문번호 %q, payload %+v, err %v", events[0].OrderNo, payload, err)
+	}
+
+	if err := store.Add(nil, events...); err != nil {
+		t.Fatal(err)
+	}
+	_ = store.Add(nil, events[0]) // 같은 ID 는 한번만 저장
+
+	relay := &OutboxRelay{Store: store, Publisher: broker, BatchSize: 2}
+
+	// 발행 실패 시 이후 이벤트는 미룸
+	broker.FailWith(errors.New("broker down"))
+	if published, err := relay.RelayOnce(context.Background()); err == nil || published != 0 {
+		t.Fatalf("published %d, err %v", published, err)
+	}
+
+	broker.FailWith(nil)
+	for i := 0; i < 3; i++ {
+		if _, err := relay.RelayOnce(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := broker.Events()
+	if len(got) != 3 {
+		t.Fatalf("발행된 이벤트 %d 개", len(got))
+	}
+	for i, event := range got {
+		if event.ID != events[i].ID {
+			t.Fatalf("발행 순서 %d: %s, want %s", i, event.TID, events[i].TID)
+		}
+	}
+	if pending, _ := store.Pending(0); len(pending) != 0 {
+		t.Fatalf("발행 후 대기 이벤트 %d 개", len(pending))
+	}
+}
+
+func TestPaymentEventsMaskBillKey(t *testing.T) {
+	must := mustEvent(t)
+	billKey := "BK-1234567890"
+
+	events := []PaymentEvent{
+		must(NewPaymentApprovedEvent(ReqTransactionAutoPay{UserID: 1}, ResTransactionAutoPay{TID: "tid", BillKey: billKey})),
+		must(NewPaymentApprovedEventFromCallback(ResPaymentData{UserID: "1", Tid: "tid", BillKey: billKey})),
+		must(NewBillKeyIssuedEvent(ResPaymentData{UserID: "1", BillKey: billKey}, "vault-id")),
+		must(NewEasyPayMethodRegisteredEvent(1, EasyPayMethod{BillKey: billKey}, "vault-id")),
+	}
+	for _, event := range events {
+		if strings.Contains(string(event.Payload), billKey) {
+			t.Errorf("%s payload 에 billkey 원문: %s", event.Type, event.Payload)
+		}
+		if !strings.Contains(string(event.Payload), "*********7890") {
+			t.Errorf("%s payload 에 마스킹한 billkey 없음: %s", event.Type, event.Payload)
+		}
+	}
+
+	var payload BillKeyIssuedPayload
+	if err := events[2].Decode(&payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.BillKeyID != "vault-id" {
+		t.Fatalf("billkey_id = %q", payload.BillKeyID)
+	}
+}
+
+func TestMaskBillKey(t *testing.T) {
+	for billKey, want := range map[string]string{"": "", "abc": "***", "abcd": "****", "abcdef": "**cdef"} {
+		if got := MaskBillKey(billKey); got != want {
+			t.Errorf("MaskBillKey(%q) = %q, want %q", billKey, got, want)
+		}
+	}
+}
@@ -8,17 +8,64 @@ import (
 	"strconv"
 )
 
+// PayLetterError 페이레터가 돌려준 에러 응답
+type PayLetterError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Server  bool   `json:"server"` // 500 응답의 error 객체, 결제 거절 등 업무 에러가 아닌 페이레터 장애
+}
+
+func (e *PayLetterError) Error() string {
+	return fmt.Sprintf("[%s]%s", e.Code, e.Message)
+}
+
+// newServerError 500 응답의 error 객체
+func newServerError(v any) error {
+	e, _ := v.(map[string]any)
+	return &PayLetterError{
+		Code:    fmt.Sprint(e["code"]),
+		Message: fmt.Sprint(e["message"]),
+		Server:  true,
+	}
+}
+
+// newResponseError code, message 가 있는 에러 응답
+func newResponseError(code, message any) error {
+	return &PayLetterError{
+		Code:    fmt.Sprint(code),
+		Message: fmt.Sprint(message),
+	}
+}
+
+// PayLetterErrorCode err 가 페이레터 에러 응답이면 에러 코드
+func PayLetterErrorCode(err error) (code string, ok bool) {
+	var e *PayLetterError
+	if errors.As(err, &e) {
+		return e.Code, true
+	}
+	return "", false
+}
+
 type PayLetter struct {
 	ClientInfo
+	options
 }
 
-func GetPayLetter(c ClientInfo) IPayLetter {
+func GetPayLetter(c ClientInfo, opts ...Option) IPayLetter {
 	return &PayLetter{
 		ClientInfo: c,
+		options:    newOptions(opts),
 	}
 }
 
 func (o *PayLetter) RegisterAutoPay(req ReqRegisterAutoPay) (res ResRegisterAutoPay, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+	if err = checkOperation(Operation.RegisterAutoPay, req.PgCode); err != nil {
+		return
+	}
+
 	paymentData := reqPaymentData{
 		PgCode:          req.PgCode,
 		ClientID:        o.ClientID,
@@ -35,6 +82,7 @@ func (o *PayLetter) RegisterAutoPay(req ReqRegisterAutoPay) (res ResRegisterAuto
 		ReturnUrl:       req.ReturnUrl,
 		CallbackUrl:     req.CallbackUrl,
 		CancelUrl:       req.CancelUrl,
+		AmountBreakdown: req.AmountBreakdown,
 	}
 
 	payLetterRes := utils.Post[utils.M](
@@ -47,13 +95,12 @@ func (o *PayLetter) RegisterAutoPay(req ReqRegisterAutoPay) (res ResRegisterAuto
 	)
 
 	if v, exists := payLetterRes["error"]; exists { // 500 error
-		e := v.(map[string]any)
-		err = errors.New(fmt.Sprintf("[%v]%v", e["code"], e["message"]))
+		err = newServerError(v)
 		return
 	}
 
 	if code, exists := payLetterRes["code"]; exists {
-		err = errors.New(fmt.Sprintf("[%v]%v", code, payLetterRes["message"]))
+		err = newResponseError(code, payLetterRes["message"])
 		return
 	}
 
@@ -66,6 +113,13 @@ func (o *PayLetter) RegisterAutoPay(req ReqRegisterAutoPay) (res ResRegisterAuto
 }
 
 func (o *PayLetter) TransactionAutoPay(req ReqTransactionAutoPay) (res ResTransactionAutoPay, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+	if err = checkOperation(Operation.TransactionAutoPay, req.PgCode); err != nil {
+		return
+	}
+
 	payLetterRes := utils.Post[utils.M](
 		transactionAutoPayUrl,
 		reqTransactionAutoPay{
@@ -78,13 +132,12 @@ func (o *PayLetter) TransactionAutoPay(req ReqTransactionAutoPay) (res ResTransa
 		},
 	)
 	if v, exists := payLetterRes["error"]; exists { // 500 error
-		e := v.(map[string]any)
-		err = errors.New(fmt.Sprintf("[%v]%v", e["code"], e["message"]))
+		err = newServerError(v)
 		return
 	}
 
 	if code, exists := payLetterRes["code"]; exists {
-		err = errors.New(fmt.Sprintf("[%v]%v", code, payLetterRes["message"]))
+		err = newResponseError(code, payLetterRes["message"])
 		return
 	}
 
@@ -99,13 +152,17 @@ func (o *PayLetter) TransactionAutoPay(req ReqTransactionAutoPay) (res ResTransa
 }
 
 func (o *PayLetter) CancelTransaction(req ReqCancelTransaction) (res ResCancelTransaction, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
 	cancelData := reqCancelTransaction{
 		ClientInfo:           o.ClientInfo,
 		ReqCancelTransaction: req,
 	}
 
 	apiKey := o.ClientInfo.PaymentAPIKey
-	if PgCode.IsNaverCode(cancelData.ReqCancelTransaction.PgCode) { // 네이버페이는 client id 와 api key 가 다름
+	if cancelData.ReqCancelTransaction.PgCode.NeedsSeparateCredentials() { // 네이버페이는 client id 와 api key 가 다름
 		apiKey = cancelData.ReqCancelTransaction.NaverAPIKey
 		cancelData.ClientInfo.ClientID = cancelData.ReqCancelTransaction.NaverAPIClientId
 	}
@@ -120,14 +177,13 @@ func (o *PayLetter) CancelTransaction(req ReqCancelTransaction) (res ResCancelTr
 	)
 
 	if v, exists := payLetterRes["error"]; exists { // 500 error
-		e := v.(map[string]any)
-		err = errors.New(fmt.Sprintf("[%v]%v", e["code"], e["message"]))
+		err = newServerError(v)
 		return
 	}
 
 	if code, exists := payLetterRes["code"]; exists {
 		// 에러 발생
-		err = errors.New(fmt.Sprintf("[%v]%v", code, payLetterRes["message"]))
+		err = newResponseError(code, payLetterRes["message"])
 		return
 	}
 
@@ -141,13 +197,20 @@ func (o *PayLetter) CancelTransaction(req ReqCancelTransaction) (res ResCancelTr
 }
 
 func (o *PayLetter) PartialCancelTransaction(req ReqPartialCancelTransaction) (res ResPartialCancelTransaction, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+	if err = checkOperation(Operation.PartialCancelTransaction, req.PgCode); err != nil {
+		return
+	}
+
 	cancelData := reqPartialCancelTransaction{
 		ClientInfo:                  o.ClientInfo,
 		ReqPartialCancelTransaction: req,
 	}
 
 	apiKey := o.ClientInfo.PaymentAPIKey
-	if PgCode.IsNaverCode(cancelData.ReqPartialCancelTransaction.PgCode) { // 네이버페이는 client id 와 api key 가 다름
+	if cancelData.ReqPartialCancelTransaction.PgCode.NeedsSeparateCredentials() { // 네이버페이는 client id 와 api key 가 다름
 		apiKey = cancelData.ReqPartialCancelTransaction.NaverAPIKey
 		cancelData.ClientInfo.ClientID = cancelData.ReqPartialCancelTransaction.NaverAPIClientId
 	}
@@ -162,14 +225,13 @@ func (o *PayLetter) PartialCancelTransaction(req ReqPartialCancelTransaction) (r
 	)
 
 	if v, exists := payLetterRes["error"]; exists { // 500 error
-		e := v.(map[string]any)
-		err = errors.New(fmt.Sprintf("[%v]%v", e["code"], e["message"]))
+		err = newServerError(v)
 		return
 	}
 
 	if code, exists := payLetterRes["code"]; exists {
 		// 에러 발생
-		err = errors.New(fmt.Sprintf("[%v]%v", code, payLetterRes["message"]))
+		err = newResponseError(code, payLetterRes["message"])
 		return
 	}
 
@@ -183,8 +245,13 @@ func (o *PayLetter) PartialCancelTransaction(req ReqPartialCancelTransaction) (r
 }
 
 func (o *PayLetter) RegisterEasyPay(req ReqRegisterEasyPay) (payLetterRes ResEasyPayUI, err error) {
+	req.setReqDate(o.reqDate())
+	if err = req.Validate(); err != nil {
+		return
+	}
+
 	req.setClientID(o.ClientID)
-	req.setHashData(o.PaymentAPIKey, o.ClientID)
+	req.setHashData(o.Signer())
 
 	payLetterRes = utils.Post[ResEasyPayUI](
 		easyPayRegisterUrl,
@@ -197,18 +264,23 @@ func (o *PayLetter) RegisterEasyPay(req ReqRegisterEasyPay) (payLetterRes ResEas
 
 	if payLetterRes.Code != nil {
 		// 에러 발생
-		err = errors.New(fmt.Sprintf("[%d]%s", *payLetterRes.Code, payLetterRes.Message))
+		err = newResponseError(*payLetterRes.Code, payLetterRes.Message)
 		return
 	}
 	return
 }
 
 func (o *PayLetter) GetRegisteredEasyPayMethods(req ReqGetRegisteredEasyPayMethod) (payLetterRes ResPayLetterGetEasyPayMethods, err error) {
+	req.setReqDate(o.reqDate())
+	if err = req.Validate(); err != nil {
+		return
+	}
+
 	params := map[string]string{
 		"client_id": o.ClientID,
 		"user_id":   strconv.Itoa(req.UserID),
 		"req_date":  req.ReqDate,
-		"hash_data": req.createHashData(o.PaymentAPIKey, o.ClientID),
+		"hash_data": req.createHashData(o.Signer()),
 	}
 
 	payLetterRes = utils.Get[ResPayLetterGetEasyPayMethods](
@@ -220,7 +292,7 @@ func (o *PayLetter) GetRegisteredEasyPayMethods(req ReqGetRegisteredEasyPayMetho
 		},
 	)
 	if payLetterRes.Code != nil {
-		err = errors.New(fmt.Sprintf("[%d]%s", *payLetterRes.Code, payLetterRes.Message))
+		err = newResponseError(*payLetterRes.Code, payLetterRes.Message)
 		return
 	}
 
@@ -234,9 +306,9 @@ func (o *PayLetter) GetRegisteredEasyPayMethods(req ReqGetRegisteredEasyPayMetho
 
 	for idx, method := range payLetterRes.MethodList {
 		switch method.PaymentMethod {
-		case PgCode.CreditCard:
+		case PgCodeCreditCard:
 			method.MethodName = CardCode.ValueMap[method.MethodCode]
-		case PgCode.EasyBank:
+		case PgCodeEasyBank:
 			method.MethodName = BankCode[method.MethodCode]
 		}
 		payLetterRes.MethodList[idx] = method
@@ -246,9 +318,14 @@ func (o *PayLetter) GetRegisteredEasyPayMethods(req ReqGetRegisteredEasyPayMetho
 }
 
 func (o *PayLetter) CancelEasyPay(req ReqCancelEasyPay) (payLetterRes ResCancelEasyPay, err error) {
+	req.setReqDate(o.reqDate())
+	if err = req.Validate(); err != nil {
+		return
+	}
+
 	req.setClientID(o.ClientID)
 	req.setIPAddress(o.IpAddr)
-	req.setHashData(o.ClientID, o.PaymentAPIKey)
+	req.setHashData(o.Signer())
 
 	payLetterRes = utils.Post[ResCancelEasyPay](
 		easyPayCancelUrl,
@@ -259,7 +336,7 @@ func (o *PayLetter) CancelEasyPay(req ReqCancelEasyPay) (payLetterRes ResCancelE
 		},
 	)
 	if payLetterRes.Code != nil {
-		err = errors.New(fmt.Sprintf("[%d]%s", *payLetterRes.Code, payLetterRes.Message))
+		err = newResponseError(*payLetterRes.Code, payLetterRes.Message)
 		return
 	}
 
@@ -267,6 +344,14 @@ func (o *PayLetter) CancelEasyPay(req ReqCancelEasyPay) (payLetterRes ResCancelE
 }
 
 func (o *PayLetter) TransactionEasyPay(req ReqTransactionEasyPay) (payLetterRes ResEasyPayUI, err error) {
+	req.setReqDate(o.reqDate())
+	if err = req.Validate(); err != nil {
+		return
+	}
+	if err = checkEasyPayOptions(req); err != nil {
+		return
+	}
+
 	paymentData := reqPaymentData{
 		PgCode:          req.PgCode,
 		ClientID:        o.ClientID,
@@ -285,11 +370,12 @@ func (o *PayLetter) TransactionEasyPay(req ReqTransactionEasyPay) (payLetterRes
 		CallbackUrl:     req.CallbackUrl,
 		CancelUrl:       req.CancelUrl,
 		ReqDate:         req.ReqDate,
-		HashData:        req.createHashData(o.ClientID, o.PaymentAPIKey),
+		HashData:        req.createHashData(o.Signer()),
 		BillKey:         req.BillKey,
 		ReceiptType:     req.ReceiptType,
 		ReceiptInfo:     req.ReceiptInfo,
 		InstallMonth:    fmt.Sprintf("%02d", req.InstallMonth),
+		AmountBreakdown: req.AmountBreakdown,
 	}
 
 	payLetterRes = utils.Post[ResEasyPayUI](
@@ -302,7 +388,7 @@ func (o *PayLetter) TransactionEasyPay(req ReqTransactionEasyPay) (payLetterRes
 	)
 
 	if payLetterRes.Code != nil {
-		err = errors.New(fmt.Sprintf("[%d]%s", *payLetterRes.Code, payLetterRes.Message))
+		err = newResponseError(*payLetterRes.Code, payLetterRes.Message)
 		return
 	}
 
@@ -310,6 +396,10 @@ func (o *PayLetter) TransactionEasyPay(req ReqTransactionEasyPay) (payLetterRes
 }
 
 func (o *PayLetter) TransactionNormalPay(req ReqTransactionNormalPay) (payLetterRes ResTransactionNormalPay, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
 	paymentData := reqPaymentData{
 		PgCode:          req.PgCode,
 		ServiceName:     req.ServiceName,
@@ -326,10 +416,11 @@ func (o *PayLetter) TransactionNormalPay(req ReqTransactionNormalPay) (payLetter
 		ReturnUrl:       req.ReturnUrl,
 		CallbackUrl:     req.CallbackUrl,
 		CancelUrl:       req.CancelUrl,
+		AmountBreakdown: req.AmountBreakdown,
 	}
 
 	apiKey := o.PaymentAPIKey
-	if PgCode.IsNaverCode(req.PgCode) { // 네이버페이는 client id 와 api key 가 다름
+	if req.PgCode.NeedsSeparateCredentials() { // 네이버페이는 client id 와 api key 가 다름
 		apiKey = req.NaverAPIKey
 		paymentData.ClientID = req.NaverAPIClientId
 	}
@@ -344,28 +435,26 @@ func (o *PayLetter) TransactionNormalPay(req ReqTransactionNormalPay) (payLetter
 	)
 
 	if payLetterRes.Code != nil {
-		err = errors.New(fmt.Sprintf("[%d]%s", *payLetterRes.Code, payLetterRes.Message))
+		err = newResponseError(*payLetterRes.Code, payLetterRes.Message)
 		return
 	}
 	return
 }
 
 func (o *PayLetter) GetTransactionList(req ReqGetTransactionList) (res ResGetTransactionList, err error) {
-	switch req.DateType {
-	case TransactionDateType.Transaction, TransactionDateType.Settle:
-	default:
-		err = errors.New("유효하지 않은 date type")
+	if err = req.Validate(); err != nil {
+		return
 	}
 
 	reqParam := map[string]string{
 		"date":      req.Date,
 		"date_type": req.DateType,
-		"pgcode":    req.PgCode,
+		"pgcode":    req.PgCode.String(),
 		"client_id": o.ClientID,
 	}
 
 	apiKey := o.SearchAPIKey
-	if PgCode.IsNaverCode(req.PgCode) { // 네이버페이는 client id 와 api key 가 다름
+	if req.PgCode.NeedsSeparateCredentials() { // 네이버페이는 client id 와 api key 가 다름
 		apiKey = req.NaverAPISearchKey
 		reqParam["client_id"] = req.NaverAPIClientID
 	}
@@ -380,8 +469,458 @@ func (o *PayLetter) GetTransactionList(req ReqGetTransactionList) (res ResGetTra
 	)
 
 	if res.Code != nil {
-		err = errors.New(fmt.Sprintf("[%d]%s", *res.Code, *res.Message))
+		err = newResponseError(*res.Code, *res.Message)
+	}
+
+	return
+}
+
+func (o *PayLetter) IssueVirtualAccount(req ReqIssueVirtualAccount) (payLetterRes ResTransactionNormalPay, err error) {
+	req.PgCode = PgCodeVirtualAccount
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	paymentData := reqPaymentData{
+		PgCode:          req.PgCode,
+		ServiceName:     req.ServiceName,
+		ClientID:        o.ClientID,
+		UserID:          int64(req.UserID),
+		UserName:        req.UserName,
+		OrderNo:         req.OrderNo,
+		Amount:          req.Amount,
+		ProductName:     req.ProductName,
+		EmailFlag:       req.EmailFlag,
+		EmailAddr:       req.EmailAddr,
+		AutoPayFlag:     "N",
+		CustomParameter: strconv.Itoa(req.CustomParameter),
+		ReturnUrl:       req.ReturnUrl,
+		CallbackUrl:     req.CallbackUrl,
+		CancelUrl:       req.CancelUrl,
+		BankCode:        req.BankCode,
+		DepositorName:   req.DepositorName,
+		AmountBreakdown: req.AmountBreakdown,
+	}
+	if !req.ExpireAt.IsZero() {
+		paymentData.ExpireDate = req.ExpireAt.In(kst).Format(reqDateLayout)
+	}
+
+	payLetterRes = utils.Post[ResTransactionNormalPay](
+		normalTransactionUrl,
+		paymentData,
+		http.Header{
+			"Authorization": []string{fmt.Sprintf("PLKEY %s", o.PaymentAPIKey)},
+			"Content-Type":  []string{"application/json"},
+		},
+	)
+
+	if payLetterRes.Code != nil {
+		err = newResponseError(*payLetterRes.Code, payLetterRes.Message)
+		return
+	}
+	return
+}
+
+func (o *PayLetter) RefundVirtualAccount(req ReqRefundVirtualAccount) (res ResCancelTransaction, err error) {
+	req.PgCode = PgCodeVirtualAccount
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	payLetterRes := utils.Post[utils.M](
+		cancelTransactionUrl,
+		reqRefundVirtualAccount{
+			ClientInfo:              o.ClientInfo,
+			ReqRefundVirtualAccount: req,
+		},
+		http.Header{
+			"Authorization": []string{fmt.Sprintf("PLKEY %s", o.PaymentAPIKey)},
+			"Content-Type":  []string{"application/json"},
+		},
+	)
+
+	if v, exists := payLetterRes["error"]; exists { // 500 error
+		err = newServerError(v)
+		return
+	}
+
+	if code, exists := payLetterRes["code"]; exists {
+		err = newResponseError(code, payLetterRes["message"])
+		return
+	}
+
+	res = ResCancelTransaction{
+		TID:    payLetterRes["tid"].(string),
+		CID:    payLetterRes["cid"].(string),
+		Amount: utils.Any2IntMust(payLetterRes["amount"]),
+	}
+
+	return
+}
+
+func (o *PayLetter) IssueCashReceipt(req ReqIssueCashReceipt) (res ResCashReceipt, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+	if err = checkOperation(Operation.IssueCashReceipt, req.PgCode); err != nil {
+		return
+	}
+
+	res = utils.Post[ResCashReceipt](
+		cashReceiptIssueUrl,
+		reqIssueCashReceipt{
+			ClientInfo:          o.ClientInfo,
+			ReqIssueCashReceipt: req,
+		},
+		http.Header{
+			"Authorization": []string{fmt.Sprintf("PLKEY %s", o.PaymentAPIKey)},
+			"Content-Type":  []string{"application/json"},
+		},
+	)
+
+	if res.Code != nil {
+		err = newResponseError(*res.Code, res.Message)
+	}
+
+	return
+}
+
+// ReissueCashReceipt 용도나 발행 번호를 바꿀 때 사용, 기존 영수증이 이미 취소되어 있으면 발행만 함
+func (o *PayLetter) ReissueCashReceipt(req ReqIssueCashReceipt) (res ResCashReceipt, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+	if err = checkOperation(Operation.ReissueCashReceipt, req.PgCode); err != nil {
+		return
+	}
+
+	current, err := o.GetCashReceipt(ReqGetCashReceipt{PgCode: req.PgCode, TID: req.TID})
+	if err != nil {
+		return
+	}
+
+	if !current.IsCancelled() {
+		if _, err = o.CancelCashReceipt(ReqCancelCashReceipt{PgCode: req.PgCode, UserID: req.UserID, TID: req.TID}); err != nil {
+			return
+		}
+	}
+
+	return o.IssueCashReceipt(req)
+}
+
+func (o *PayLetter) CancelCashReceipt(req ReqCancelCashReceipt) (res ResCashReceipt, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	res = utils.Post[ResCashReceipt](
+		cashReceiptCancelUrl,
+		reqCancelCashReceipt{
+			ClientInfo:           o.ClientInfo,
+			ReqCancelCashReceipt: req,
+		},
+		http.Header{
+			"Authorization": []string{fmt.Sprintf("PLKEY %s", o.PaymentAPIKey)},
+			"Content-Type":  []string{"application/json"},
+		},
+	)
+
+	if res.Code != nil {
+		err = newResponseError(*res.Code, res.Message)
+	}
+
+	return
+}
+
+func (o *PayLetter) GetCashReceipt(req ReqGetCashReceipt) (res ResCashReceipt, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	res = utils.Get[ResCashReceipt](
+		cashReceiptStatusUrl,
+		map[string]string{
+			"client_id": o.ClientID,
+			"pgcode":    req.PgCode.String(),
+			"tid":       req.TID,
+		},
+		http.Header{
+			"Authorization": []string{fmt.Sprintf("PLKEY %s", o.SearchAPIKey)},
+			"Content-Type":  []string{"application/json"},
+		},
+	)
+
+	if res.Code != nil {
+		err = newResponseError(*res.Code, res.Message)
+	}
+
+	return
+}
+
+func (o *PayLetter) GetTransaction(req ReqGetTransaction) (res ResGetTransaction, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	reqParam := map[string]string{
+		"pgcode":    req.PgCode.String(),
+		"client_id": o.ClientID,
+	}
+	if req.TID != "" {
+		reqParam["tid"] = req.TID
+	} else {
+		reqParam["order_no"] = req.OrderNo
 	}
 
+	apiKey := o.SearchAPIKey
+	if req.PgCode.NeedsSeparateCredentials() { // 네이버페이는 client id 와 api key 가 다름
+		apiKey = req.NaverAPISearchKey
+		reqParam["client_id"] = req.NaverAPIClientID
+	}
+
+	res = utils.Get[ResGetTransaction](
+		getTransactionUrl,
+		reqParam,
+		http.Header{
+			"Authorization": []string{fmt.Sprintf("PLKEY %s", apiKey)},
+			"Content-Type":  []string{"application/json"},
+		},
+	)
+
+	fallback, err := transactionLookupResult(res)
+	if !fallback {
+		return
+	}
+
+	// 단건 조회를 지원하지 않는 결제 수단이거나 거래가 없다는 응답이면 거래 일자 목록에서 찾음
+	return o.findTransaction(req)
+}
+
+// TransactionNotFoundCodes 단건 조회에서 거래가 없다는 페이레터 에러 코드, 이 코드일 때만 거래 목록에서 다시 찾음
+var TransactionNotFoundCodes = []string{"404"}
+
+// transactionLookupResult 단건 조회 응답 판단, fallback = true 이면 거래 목록에서 다시 찾음
+//
+// 단건 조회를 지원하지 않는 결제 수단은 빈 응답, 그 외 에러 응답은 그대로 반환
+func transactionLookupResult(res ResGetTransaction) (fallback bool, err error) {
+	if res.Code == nil {
+		return res.TID == "", nil
+	}
+
+	code := strconv.Itoa(*res.Code)
+	for _, notFound := range TransactionNotFoundCodes {
+		if code == notFound {
+			return true, nil
+		}
+	}
+
+	message := ""
+	if res.Message != nil {
+		message = *res.Message
+	}
+	return false, newResponseError(code, message)
+}
+
+func (o *PayLetter) findTransaction(req ReqGetTransaction) (res ResGetTransaction, err error) {
+	date := req.Date
+	if date == "" {
+		date = o.now().Format(transactionDateLayout)
+	}
+
+	list, err := o.GetTransactionList(ReqGetTransactionList{
+		Date:              date,
+		DateType:          TransactionDateType.Transaction,
+		PgCode:            req.PgCode,
+		NaverAPIClientID:  req.NaverAPIClientID,
+		NaverAPISearchKey: req.NaverAPISearchKey,
+	})
+	if err != nil {
+		return
+	}
+
+	rows := findTransactions(list.List, req.TID, req.OrderNo)
+	if len(rows) == 0 {
+		err = fmt.Errorf("%w: [%s]tid=%s order_no=%s", ErrTransactionNotFound, date, req.TID, req.OrderNo)
+		return
+	}
+
+	return newResGetTransaction(rows), nil
+}
+
+func (o *PayLetter) DeleteEasyPayMethod(req ReqDeleteEasyPayMethod) (payLetterRes ResUpdateEasyPayMethod, err error) {
+	req.setReqDate(o.reqDate())
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	req.setClientID(o.ClientID)
+	req.setHashData(o.Signer())
+
+	payLetterRes = utils.Post[ResUpdateEasyPayMethod](
+		easyPayDeleteMethodUrl,
+		req,
+		http.Header{
+			"Authorization": []string{fmt.Sprintf("PLKEY %s", o.PaymentAPIKey)},
+			"Content-Type":  []string{"application/json"},
+		},
+	)
+	if payLetterRes.Code != nil {
+		err = newResponseError(*payLetterRes.Code, payLetterRes.Message)
+		return
+	}
+
+	return
+}
+
+func (o *PayLetter) RenameEasyPayMethod(req ReqRenameEasyPayMethod) (payLetterRes ResUpdateEasyPayMethod, err error) {
+	req.setReqDate(o.reqDate())
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	req.setClientID(o.ClientID)
+	req.setHashData(o.Signer())
+
+	payLetterRes = utils.Post[ResUpdateEasyPayMethod](
+		easyPayRenameMethodUrl,
+		req,
+		http.Header{
+			"Authorization": []string{fmt.Sprintf("PLKEY %s", o.PaymentAPIKey)},
+			"Content-Type":  []string{"application/json"},
+		},
+	)
+	if payLetterRes.Code != nil {
+		err = newResponseError(*payLetterRes.Code, payLetterRes.Message)
+		return
+	}
+
+	return
+}
+
+func (o *PayLetter) SetFavoriteEasyPayMethod(req ReqSetFavoriteEasyPayMethod) (payLetterRes ResUpdateEasyPayMethod, err error) {
+	req.setReqDate(o.reqDate())
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	req.setClientID(o.ClientID)
+	req.setHashData(o.Signer())
+
+	payLetterRes = utils.Post[ResUpdateEasyPayMethod](
+		easyPayFavoriteMethodUrl,
+		req,
+		http.Header{
+			"Authorization": []string{fmt.Sprintf("PLKEY %s", o.PaymentAPIKey)},
+			"Content-Type":  []string{"application/json"},
+		},
+	)
+	if payLetterRes.Code != nil {
+		err = newResponseError(*payLetterRes.Code, payLetterRes.Message)
+		return
+	}
+
+	return
+}
+
+func (o *PayLetter) UnregisterEasyPay(req ReqUnregisterEasyPay) (payLetterRes ResUpdateEasyPayMethod, err error) {
+	req.setReqDate(o.reqDate())
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	req.setClientID(o.ClientID)
+	req.setHashData(o.Signer())
+
+	payLetterRes = utils.Post[ResUpdateEasyPayMethod](
+		easyPayUnregisterUrl,
+		req,
+		http.Header{
+			"Authorization": []string{fmt.Sprintf("PLKEY %s", o.PaymentAPIKey)},
+			"Content-Type":  []string{"application/json"},
+		},
+	)
+	if payLetterRes.Code != nil {
+		err = newResponseError(*payLetterRes.Code, payLetterRes.Message)
+		return
+	}
+
+	return
+}
+
+func (o *PayLetter) ResetEasyPayPassword(req ReqEasyPayPassword) (payLetterRes ResEasyPayUI, err error) {
+	req.setReqDate(o.reqDate())
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	req.setClientID(o.ClientID)
+	req.setHashData(o.Signer())
+
+	payLetterRes = utils.Post[ResEasyPayUI](
+		easyPayResetPasswordUrl,
+		req,
+		http.Header{
+			"Authorization": []string{fmt.Sprintf("PLKEY %s", o.PaymentAPIKey)},
+			"Content-Type":  []string{"application/json"},
+		},
+	)
+
+	if payLetterRes.Code != nil {
+		// 에러 발생
+		err = newResponseError(*payLetterRes.Code, payLetterRes.Message)
+		return
+	}
+	return
+}
+
+func (o *PayLetter) ChangeEasyPayPassword(req ReqEasyPayPassword) (payLetterRes ResEasyPayUI, err error) {
+	req.setReqDate(o.reqDate())
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	req.setClientID(o.ClientID)
+	req.setHashData(o.Signer())
+
+	payLetterRes = utils.Post[ResEasyPayUI](
+		easyPayChangePasswordUrl,
+		req,
+		http.Header{
+			"Authorization": []string{fmt.Sprintf("PLKEY %s", o.PaymentAPIKey)},
+			"Content-Type":  []string{"application/json"},
+		},
+	)
+
+	if payLetterRes.Code != nil {
+		// 에러 발생
+		err = newResponseError(*payLetterRes.Code, payLetterRes.Message)
+		return
+	}
+	return
+}
+
+func (o *PayLetter) SetEasyPayPasswordSkip(req ReqSetEasyPayPasswordSkip) (payLetterRes ResEasyPayUI, err error) {
+	req.setReqDate(o.reqDate())
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	req.setClientID(o.ClientID)
+	req.setHashData(o.Signer())
+
+	payLetterRes = utils.Post[ResEasyPayUI](
+		easyPayPasswordSkipUrl,
+		req,
+		http.Header{
+			"Authorization": []string{fmt.Sprintf("PLKEY %s", o.PaymentAPIKey)},
+			"Content-Type":  []string{"application/json"},
+		},
+	)
+
+	if payLetterRes.Code != nil {
+		// 에러 발생
+		err = newResponseError(*payLetterRes.Code, payLetterRes.Message)
+		return
+	}
 	return
 }
@@ -0,0 +1,71 @@
+package payletter
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTransactionLookupResult(t *testing.T) {
+	code := func(c int) *int { return &c }
+	message := "서버 에러"
+
+	tests := []struct {
+		name     string
+		res      ResGetTransaction
+		fallback bool
+		code     string
+	}{
+		{"found", ResGetTransaction{Transaction: Transaction{TID: "tid"}}, false, ""},
+		{"unsupported", ResGetTransaction{}, true, ""},
+		{"not found", ResGetTransaction{Code: code(404)}, true, ""},
+		{"other error", ResGetTransaction{Code: code(500), Message: &message}, false, "500"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fallback, err := transactionLookupResult(tt.res)
+			if fallback != tt.fallback {
+				t.Fatalf("fallback = %v, want %v", fallback, tt.fallback)
+			}
+			got, _ := PayLetterErrorCode(err)
+			if got != tt.code {
+				t.Fatalf("err = %v, want code %q", err, tt.code)
+			}
+		})
+	}
+}
+
+func TestNewResGetTransaction(t *testing.T) {
+	list := []Transaction{
+		{TID: "tid-1", OrderNo: "order-1", Amount: 1000, TransactionDate: "2024-03-01 12:00:00"},
+		{TID: "tid-2", OrderNo: "order-2", Amount: 500},
+		{TID: "tid-1c", OrderNo: "order-1", Amount: 300, CancelDate: "2024-03-01 13:00:00"},
+	}
+
+	res := newResGetTransaction(findTransactions(list, "tid-1", ""))
+	if res.TID != "tid-1" || res.CancelledAmount != 300 || res.RemainingAmount() != 700 || res.IsCancelled() {
+		t.Fatalf("res %+v", res)
+	}
+
+	// 원 거래가 다른 일자 목록에 있으면 취소 건만 찾음
+	res = newResGetTransaction(list[2:])
+	if !res.Incomplete || res.Amount != 0 || res.IsCancelled() || len(res.CancelHistory) != 1 || res.CancelledAmount != 300 {
+		t.Fatalf("취소 건만 있는 결과 %+v", res)
+	}
+
+	if rows := findTransactions(list, "", "order-3"); len(rows) != 0 {
+		t.Fatalf("rows %+v", rows)
+	}
+}
+
+func TestPayLetterErrorCode(t *testing.T) {
+	err := newResponseError(1001, "한도 초과")
+	if err.Error() != "[1001]한도 초과" {
+		t.Fatalf("Error() = %s", err)
+	}
+	if code, ok := PayLetterErrorCode(errors.Join(errors.New("wrapped"), err)); !ok || code != "1001" {
+		t.Fatalf("code = %q", code)
+	}
+	if _, ok := PayLetterErrorCode(errors.New("connection reset")); ok {
+		t.Fatal("페이레터 에러가 아닌데 code 있음")
+	}
+}
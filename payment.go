@@ -0,0 +1,270 @@
+package payletter
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	ErrIllegalPaymentTransition = errors.New("illegal payment transition")
+	ErrPaymentNotFound          = errors.New("payment not found")
+	ErrPaymentAlreadyExists     = errors.New("payment already exists")
+	ErrPaymentVersionConflict   = errors.New("payment version conflict")
+)
+
+type PaymentTransitionError struct {
+	OrderNo string
+	State   string // 전환 시도 시점의 상태
+	Event   string
+	Reason  string
+}
+
+func (e *PaymentTransitionError) Error() string {
+	return fmt.Sprintf("[%s]%s 상태에서 %s 불가: %s", e.OrderNo, e.State, e.Event, e.Reason)
+}
+
+func (e *PaymentTransitionError) Unwrap() error {
+	return ErrIllegalPaymentTransition
+}
+
+type PaymentTransition struct {
+	From   string    `json:"from"`
+	To     string    `json:"to"`
+	Amount int       `json:"amount"` // 승인 또는 취소 금액
+	At     time.Time `json:"at"`
+}
+
+// Payment 주문 단위 결제 상태
+type Payment struct {
+	OrderNo         string              `json:"order_no"`
+	PgCode          PgCode              `json:"pgcode"`
+	UserID          int64               `json:"user_id"`
+	Amount          int                 `json:"amount"`           // 결제 금액
+	CancelledAmount int                 `json:"cancelled_amount"` // 누적 취소 금액
+	TID             string              `json:"tid"`
+	CID             string              `json:"cid"`
+	BillKey         string              `json:"billkey"`
+	State           string              `json:"state"`
+	FailReason      string              `json:"fail_reason"`
+	History         []PaymentTransition `json:"history"`
+	Version         int                 `json:"version"` // 저장소 낙관적 잠금용
+	CreatedAt       time.Time           `json:"created_at"`
+	UpdatedAt       time.Time           `json:"updated_at"`
+	now             Clock               // 저장소에서 다시 읽은 결제는 nil, time.Now 사용
+}
+
+// NewRequestedPayment TransactionNormalPay 로 결제창 URL 을 발급한 주문
+func NewRequestedPayment(req ReqTransactionNormalPay, opts ...Option) *Payment {
+	return newRequestedPayment(req.OrderNo, req.PgCode, int64(req.UserID), req.Amount, "", opts)
+}
+
+// NewRequestedAutoPayment TransactionAutoPay 로 결제를 요청할 주문, 응답은 AuthorizeAutoPay 또는 Fail 로 반영
+func NewRequestedAutoPayment(req ReqTransactionAutoPay, opts ...Option) *Payment {
+	return newRequestedPayment(req.OrderNo, req.PgCode, req.UserID, req.Amount, req.BillKey, opts)
+}
+
+// NewRequestedEasyPayment TransactionEasyPay 로 결제창 URL 을 발급한 주문, 결제 callback 은 Authorize 로 반영
+func NewRequestedEasyPayment(req ReqTransactionEasyPay, opts ...Option) *Payment {
+	return newRequestedPayment(req.OrderNo, req.PgCode, int64(req.UserID), req.Amount, req.BillKey, opts)
+}
+
+func newRequestedPayment(orderNo string, pgCode PgCode, userID int64, amount int, billKey string, opts []Option) *Payment {
+	clock := newOptions(opts).clock
+	now := clock()
+	return &Payment{
+		OrderNo:   orderNo,
+		PgCode:    pgCode,
+		UserID:    userID,
+		Amount:    amount,
+		BillKey:   billKey,
+		State:     PaymentState.Requested,
+		CreatedAt: now,
+		UpdatedAt: now,
+		now:       clock,
+	}
+}
+
+// RemainingAmount 취소 가능한 잔액
+func (o *Payment) RemainingAmount() int {
+	return o.Amount - o.CancelledAmount
+}
+
+// Authorize 결제 callback 으로 승인 처리
+func (o *Payment) Authorize(data ResPaymentData) (err error) {
+	if err = o.checkState("authorize", PaymentState.Requested); err != nil {
+		return
+	}
+	if data.OrderNo != o.OrderNo {
+		return o.transitionError("authorize", fmt.Sprintf("주문번호 불일치 %s", data.OrderNo))
+	}
+	if data.Amount != o.Amount {
+		return o.transitionError("authorize", fmt.Sprintf("결제 금액 불일치 %d != %d", data.Amount, o.Amount))
+	}
+
+	o.TID = data.Tid
+	o.CID = data.Cid
+	o.BillKey = data.BillKey
+	o.transition(PaymentState.Authorized, data.Amount)
+	return
+}
+
+// AuthorizeAutoPay 자동 결제 응답으로 승인 처리
+func (o *Payment) AuthorizeAutoPay(res ResTransactionAutoPay) (err error) {
+	if err = o.checkState("authorize", PaymentState.Requested); err != nil {
+		return
+	}
+	if res.Amount != o.Amount {
+		return o.transitionError("authorize", fmt.Sprintf("결제 금액 불일치 %d != %d", res.Amount, o.Amount))
+	}
+
+	o.TID = res.TID
+	o.CID = res.CID
+	o.BillKey = res.BillKey
+	o.transition(PaymentState.Authorized, res.Amount)
+	return
+}
+
+// Fail 승인 전 결제 실패 처리
+func (o *Payment) Fail(reason string) (err error) {
+	if err = o.checkState("fail", PaymentState.Requested); err != nil {
+		return
+	}
+
+	o.FailReason = reason
+	o.transition(PaymentState.Failed, 0)
+	return
+}
+
+// PartialCancel 부분 취소, 잔액을 모두 취소하면 전체 취소 상태가 됨
+func (o *Payment) PartialCancel(amount int) (err error) {
+	if err = o.checkState("partial cancel", PaymentState.Authorized, PaymentState.PartiallyCancelled); err != nil {
+		return
+	}
+	if amount <= 0 {
+		return o.transitionError("partial cancel", fmt.Sprintf("유효하지 않은 취소 금액 %d", amount))
+	}
+	if amount > o.RemainingAmount() {
+		return o.transitionError("partial cancel", fmt.Sprintf("취소 금액 %d 이 잔액 %d 보다 큼", amount, o.RemainingAmount()))
+	}
+
+	o.CancelledAmount += amount
+	if o.RemainingAmount() == 0 {
+		o.transition(PaymentState.Cancelled, amount)
+	} else {
+		o.transition(PaymentState.PartiallyCancelled, amount)
+	}
+	return
+}
+
+// Cancel 남은 금액 전체 취소
+func (o *Payment) Cancel() (err error) {
+	if err = o.checkState("cancel", PaymentState.Authorized, PaymentState.PartiallyCancelled); err != nil {
+		return
+	}
+
+	amount := o.RemainingAmount()
+	o.CancelledAmount = o.Amount
+	o.transition(PaymentState.Cancelled, amount)
+	return
+}
+
+func (o *Payment) checkState(event string, allowed ...string) error {
+	for _, state := range allowed {
+		if o.State == state {
+			return nil
+		}
+	}
+	return o.transitionError(event, "허용되지 않은 상태")
+}
+
+func (o *Payment) transitionError(event, reason string) error {
+	return &PaymentTransitionError{
+		OrderNo: o.OrderNo,
+		State:   o.State,
+		Event:   event,
+		Reason:  reason,
+	}
+}
+
+func (o *Payment) transition(to string, amount int) {
+	now := time.Now()
+	if o.now != nil {
+		now = o.now()
+	}
+	o.History = append(o.History, PaymentTransition{
+		From:   o.State,
+		To:     to,
+		Amount: amount,
+		At:     now,
+	})
+	o.State = to
+	o.UpdatedAt = now
+}
+
+type IPaymentStore interface {
+	// Create 새 결제 저장, 같은 주문번호가 있으면 ErrPaymentAlreadyExists
+	Create(p *Payment) error
+	// Get 주문번호로 결제 조회, 없으면 ErrPaymentNotFound
+	Get(orderNo string) (Payment, error)
+	// Update 저장된 버전이 p.Version 과 같을 때만 저장하고 p.Version 을 증가, 다르면 ErrPaymentVersionConflict
+	Update(p *Payment) error
+}
+
+type MemoryPaymentStore struct {
+	mu       sync.RWMutex
+	payments map[string]Payment
+}
+
+func NewMemoryPaymentStore() *MemoryPaymentStore {
+	return &MemoryPaymentStore{
+		payments: make(map[string]Payment),
+	}
+}
+
+func (o *MemoryPaymentStore) Create(p *Payment) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, exists := o.payments[p.OrderNo]; exists {
+		return ErrPaymentAlreadyExists
+	}
+
+	p.Version = 1
+	o.payments[p.OrderNo] = p.clone()
+	return nil
+}
+
+func (o *MemoryPaymentStore) Get(orderNo string) (Payment, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	p, exists := o.payments[orderNo]
+	if !exists {
+		return Payment{}, ErrPaymentNotFound
+	}
+	return p.clone(), nil
+}
+
+func (o *MemoryPaymentStore) Update(p *Payment) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	stored, exists := o.payments[p.OrderNo]
+	if !exists {
+		return ErrPaymentNotFound
+	}
+	if stored.Version != p.Version {
+		return ErrPaymentVersionConflict
+	}
+
+	p.Version++
+	o.payments[p.OrderNo] = p.clone()
+	return nil
+}
+
+func (o Payment) clone() Payment {
+	o.History = append([]PaymentTransition(nil), o.History...)
+	return o
+}
@@ -0,0 +1,146 @@
+package payletter
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestPayment() *Payment {
+	return NewRequestedPayment(ReqTransactionNormalPay{CommonTransactionData: CommonTransactionData{
+		PgCode:  PgCodeCreditCard,
+		UserID:  1,
+		OrderNo: "order-1",
+		Amount:  10000,
+	}})
+}
+
+func TestPaymentLifecycle(t *testing.T) {
+	p := newTestPayment()
+	if err := p.Authorize(ResPaymentData{OrderNo: "order-1", Tid: "tid-1", Amount: 10000}); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.PartialCancel(3000); err != nil {
+		t.Fatal(err)
+	}
+	if p.State != PaymentState.PartiallyCancelled || p.RemainingAmount() != 7000 {
+		t.Fatalf("state %s, remaining %d", p.State, p.RemainingAmount())
+	}
+	if err := p.PartialCancel(7000); err != nil {
+		t.Fatal(err)
+	}
+	if p.State != PaymentState.Cancelled || p.RemainingAmount() != 0 {
+		t.Fatalf("잔액 전체 부분 취소 후 state %s", p.State)
+	}
+
+	want := []string{PaymentState.Authorized, PaymentState.PartiallyCancelled, PaymentState.Cancelled}
+	if len(p.History) != len(want) {
+		t.Fatalf("history %+v", p.History)
+	}
+	for i, transition := range p.History {
+		if transition.To != want[i] {
+			t.Fatalf("history[%d] = %s, want %s", i, transition.To, want[i])
+		}
+	}
+}
+
+func TestPaymentIllegalTransitions(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(p *Payment) error
+	}{
+		{"amount mismatch", func(p *Payment) error {
+			return p.Authorize(ResPaymentData{OrderNo: "order-1", Amount: 1})
+		}},
+		{"order mismatch", func(p *Payment) error {
+			return p.Authorize(ResPaymentData{OrderNo: "order-2", Amount: 10000})
+		}},
+		{"cancel before authorize", func(p *Payment) error {
+			return p.Cancel()
+		}},
+		{"authorize twice", func(p *Payment) error {
+			_ = p.AuthorizeAutoPay(ResTransactionAutoPay{TID: "tid-1", Amount: 10000})
+			return p.AuthorizeAutoPay(ResTransactionAutoPay{TID: "tid-2", Amount: 10000})
+		}},
+		{"over refund", func(p *Payment) error {
+			_ = p.AuthorizeAutoPay(ResTransactionAutoPay{TID: "tid-1", Amount: 10000})
+			return p.PartialCancel(10001)
+		}},
+		{"fail after authorize", func(p *Payment) error {
+			_ = p.AuthorizeAutoPay(ResTransactionAutoPay{TID: "tid-1", Amount: 10000})
+			return p.Fail("timeout")
+		}},
+		{"cancel after cancel", func(p *Payment) error {
+			_ = p.AuthorizeAutoPay(ResTransactionAutoPay{TID: "tid-1", Amount: 10000})
+			_ = p.Cancel()
+			return p.Cancel()
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPayment()
+			err := tt.run(p)
+
+			var transitionErr *PaymentTransitionError
+			if !errors.Is(err, ErrIllegalPaymentTransition) || !errors.As(err, &transitionErr) {
+				t.Fatalf("err = %v, want ErrIllegalPaymentTransition", err)
+			}
+			if transitionErr.State != p.State {
+				t.Fatalf("에러 상태 %s, 결제 상태 %s", transitionErr.State, p.State)
+			}
+		})
+	}
+}
+
+func TestMemoryPaymentStoreOptimisticLock(t *testing.T) {
+	store := NewMemoryPaymentStore()
+	p := newTestPayment()
+	if err := store.Create(p); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Create(newTestPayment()); !errors.Is(err, ErrPaymentAlreadyExists) {
+		t.Fatalf("err = %v", err)
+	}
+
+	first, _ := store.Get("order-1")
+	second, _ := store.Get("order-1")
+
+	_ = first.Authorize(ResPaymentData{OrderNo: "order-1", Tid: "tid-1", Amount: 10000})
+	if err := store.Update(&first); err != nil {
+		t.Fatal(err)
+	}
+	_ = second.Fail("timeout")
+	if err := store.Update(&second); !errors.Is(err, ErrPaymentVersionConflict) {
+		t.Fatalf("err = %v, want ErrPaymentVersionConflict", err)
+	}
+
+	stored, _ := store.Get("order-1")
+	if stored.State != PaymentState.Authorized || stored.Version != 2 {
+		t.Fatalf("stored %+v", stored)
+	}
+	if _, err := store.Get("order-2"); !errors.Is(err, ErrPaymentNotFound) {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestAutoPaymentUsesClock(t *testing.T) {
+	clock := newFixedClock(time.Date(2024, 3, 1, 12, 0, 0, 0, kst))
+	p := NewRequestedAutoPayment(ReqTransactionAutoPay{
+		PgCode:  PgCodeCreditCard,
+		UserID:  1,
+		OrderNo: "order-1",
+		Amount:  10000,
+		BillKey: "billkey",
+	}, WithClock(clock.Now))
+	if !p.CreatedAt.Equal(clock.Now()) || p.BillKey != "billkey" {
+		t.Fatalf("payment %+v", p)
+	}
+
+	clock.Advance(time.Minute)
+	if err := p.AuthorizeAutoPay(ResTransactionAutoPay{TID: "tid-1", Amount: 10000}); err != nil {
+		t.Fatal(err)
+	}
+	if p.State != PaymentState.Authorized || !p.UpdatedAt.Equal(clock.Now()) || !p.History[0].At.Equal(clock.Now()) {
+		t.Fatalf("state %s, updated at %s", p.State, p.UpdatedAt)
+	}
+}
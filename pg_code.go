@@ -0,0 +1,174 @@
+package payletter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// PgCode 페이레터 결제 수단 코드
+type PgCode string
+
+const (
+	PgCodeCreditCard     PgCode = "creditcard"     // 신용카드
+	PgCodeBankTransfer   PgCode = "banktransfer"   // 실시간 계좌이체
+	PgCodeVirtualAccount PgCode = "virtualaccount" // 가상계좌
+	PgCodeMobile         PgCode = "mobile"         // 휴대폰 결제
+	PgCodeEasyBank       PgCode = "easybank"       // 간편 계좌이체
+	PgCodeKakaoPay       PgCode = "kakaopay"       // 카카오페이
+	PgCodeNaverPay       PgCode = "naverpay"       // 네이버페이
+	PgCodeNaverCard      PgCode = "navercard"      // 네이버페이 카드
+	PgCodeNaverPoint     PgCode = "naverpoint"     // 네이버페이 포인트
+	PgCodePayco          PgCode = "payco"          // 페이코
+	PgCodeTossPay        PgCode = "tosspay"        // 토스페이
+	PgCodeSsgPay         PgCode = "ssgpay"         // SSG 페이
+	PgCodeCultureland    PgCode = "cultureland"    // 컬쳐랜드 문화상품권
+	PgCodeSmartCulture   PgCode = "smartculture"   // 스마트 문화상품권
+	PgCodeBooknlife      PgCode = "booknlife"      // 도서문화상품권
+	PgCodeHappyMoney     PgCode = "happymoney"     // 해피머니 상품권
+	PgCodeTeenCash       PgCode = "teencash"       // 틴캐시
+)
+
+// PgCodeCapability 결제 수단별 지원 기능
+type PgCodeCapability struct {
+	Name                string `json:"name"`
+	AutoPay             bool   `json:"auto_pay"`             // 자동 결제 (billkey)
+	PartialCancel       bool   `json:"partial_cancel"`       // 부분 취소
+	CashReceipt         bool   `json:"cash_receipt"`         // 현금영수증 발행
+	Installment         bool   `json:"installment"`          // 할부
+	SeparateCredentials bool   `json:"separate_credentials"` // 별도 client id, api key 사용 (네이버페이)
+}
+
+var pgCodeCapabilities = map[PgCode]PgCodeCapability{
+	PgCodeCreditCard:     {Name: "신용카드", AutoPay: true, PartialCancel: true, Installment: true},
+	PgCodeBankTransfer:   {Name: "계좌이체", PartialCancel: true, CashReceipt: true},
+	PgCodeVirtualAccount: {Name: "가상계좌", CashReceipt: true},
+	PgCodeMobile:         {Name: "휴대폰 결제", AutoPay: true},
+	PgCodeEasyBank:       {Name: "간편 계좌이체", AutoPay: true, PartialCancel: true, CashReceipt: true},
+	PgCodeKakaoPay:       {Name: "카카오페이", AutoPay: true, PartialCancel: true},
+	PgCodeNaverPay:       {Name: "네이버페이", PartialCancel: true, SeparateCredentials: true}, // 자동 결제 API 는 네이버페이 인증 정보를 받지 않음
+	PgCodeNaverCard:      {Name: "네이버페이 카드", PartialCancel: true, SeparateCredentials: true},
+	PgCodeNaverPoint:     {Name: "네이버페이 포인트", PartialCancel: true, SeparateCredentials: true},
+	PgCodePayco:          {Name: "페이코", PartialCancel: true},
+	PgCodeTossPay:        {Name: "토스페이", PartialCancel: true},
+	PgCodeSsgPay:         {Name: "SSG 페이", PartialCancel: true},
+	PgCodeCultureland:    {Name: "컬쳐랜드 문화상품권"},
+	PgCodeSmartCulture:   {Name: "스마트 문화상품권"},
+	PgCodeBooknlife:      {Name: "도서문화상품권"},
+	PgCodeHappyMoney:     {Name: "해피머니 상품권"},
+	PgCodeTeenCash:       {Name: "틴캐시"},
+}
+
+// PgCodes 지원하는 모든 결제 수단 코드
+func PgCodes() []PgCode {
+	return []PgCode{
+		PgCodeCreditCard,
+		PgCodeBankTransfer,
+		PgCodeVirtualAccount,
+		PgCodeMobile,
+		PgCodeEasyBank,
+		PgCodeKakaoPay,
+		PgCodeNaverPay,
+		PgCodeNaverCard,
+		PgCodeNaverPoint,
+		PgCodePayco,
+		PgCodeTossPay,
+		PgCodeSsgPay,
+		PgCodeCultureland,
+		PgCodeSmartCulture,
+		PgCodeBooknlife,
+		PgCodeHappyMoney,
+		PgCodeTeenCash,
+	}
+}
+
+func (c PgCode) String() string {
+	return string(c)
+}
+
+func (c PgCode) IsValid() bool {
+	_, exists := pgCodeCapabilities[c]
+	return exists
+}
+
+// Capability 알 수 없는 코드는 모든 기능이 false
+func (c PgCode) Capability() PgCodeCapability {
+	return pgCodeCapabilities[c]
+}
+
+// NeedsSeparateCredentials 네이버페이는 client id 와 api key 가 다름
+func (c PgCode) NeedsSeparateCredentials() bool {
+	return c.Capability().SeparateCredentials
+}
+
+func (c PgCode) MarshalText() ([]byte, error) {
+	return []byte(c), nil
+}
+
+// UnmarshalText 대소문자를 구분하지 않음, 페이레터가 새 코드를 추가해도 callback 을 받을 수 있도록 알 수 없는 코드도 허용
+func (c *PgCode) UnmarshalText(text []byte) error {
+	*c = PgCode(strings.ToLower(strings.TrimSpace(string(text))))
+	return nil
+}
+
+var ErrUnsupportedOperation = errors.New("결제 수단이 지원하지 않는 기능")
+
+type UnsupportedOperationError struct {
+	Operation string
+	PgCode    PgCode
+	Feature   string // 요청에 포함된 옵션 중 지원하지 않는 기능 (할부, 현금영수증), operation 자체를 지원하지 않으면 빈 값
+}
+
+func (e *UnsupportedOperationError) Error() string {
+	if e.Feature != "" {
+		return fmt.Sprintf("[%s]%s 의 %s 지원하지 않음", e.PgCode, e.Operation, e.Feature)
+	}
+	return fmt.Sprintf("[%s]%s 지원하지 않음", e.PgCode, e.Operation)
+}
+
+func (e *UnsupportedOperationError) Unwrap() error {
+	return ErrUnsupportedOperation
+}
+
+// operationRequirements operation 별 필요한 결제 수단 기능, 없는 operation 은 모든 결제 수단에서 사용 가능
+var operationRequirements = map[string]func(c PgCodeCapability) bool{
+	Operation.RegisterAutoPay:          func(c PgCodeCapability) bool { return c.AutoPay },
+	Operation.TransactionAutoPay:       func(c PgCodeCapability) bool { return c.AutoPay },
+	Operation.PartialCancelTransaction: func(c PgCodeCapability) bool { return c.PartialCancel },
+	Operation.IssueCashReceipt:         func(c PgCodeCapability) bool { return c.CashReceipt },
+	Operation.ReissueCashReceipt:       func(c PgCodeCapability) bool { return c.CashReceipt },
+}
+
+// Capabilities 결제 수단별 지원 기능 (결제 화면 구성용), 알 수 없는 결제 수단이면 ok = false
+func Capabilities(pgCode PgCode) (capability PgCodeCapability, ok bool) {
+	capability, ok = pgCodeCapabilities[pgCode]
+	return
+}
+
+// Supports operation 을 이 결제 수단으로 호출할 수 있는지 여부
+func (c PgCode) Supports(operation string) bool {
+	if !c.IsValid() {
+		return false
+	}
+	requirement, exists := operationRequirements[operation]
+	return !exists || requirement(c.Capability())
+}
+
+func checkOperation(operation string, pgCode PgCode) error {
+	if !pgCode.Supports(operation) {
+		return &UnsupportedOperationError{Operation: operation, PgCode: pgCode}
+	}
+	return nil
+}
+
+// checkEasyPayOptions 할부, 현금영수증 옵션을 결제 수단이 지원하는지 검사
+func checkEasyPayOptions(req ReqTransactionEasyPay) error {
+	capability := req.PgCode.Capability()
+	if req.InstallMonth >= 2 && !capability.Installment {
+		return &UnsupportedOperationError{Operation: Operation.TransactionEasyPay, PgCode: req.PgCode, Feature: "할부"}
+	}
+	if req.ReceiptFlag == "Y" && !capability.CashReceipt {
+		return &UnsupportedOperationError{Operation: Operation.TransactionEasyPay, PgCode: req.PgCode, Feature: "현금영수증"}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package payletter
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPgCodeSupports(t *testing.T) {
+	tests := []struct {
+		pgCode    PgCode
+		operation string
+		want      bool
+	}{
+		{PgCodeCreditCard, Operation.TransactionAutoPay, true},
+		{PgCodeNaverPay, Operation.RegisterAutoPay, false},
+		{PgCodeNaverPay, Operation.TransactionAutoPay, false},
+		{PgCodeNaverPay, Operation.PartialCancelTransaction, true},
+		{PgCodeVirtualAccount, Operation.PartialCancelTransaction, false},
+		{"unknown", Operation.CancelTransaction, false},
+	}
+	for _, tt := range tests {
+		if got := tt.pgCode.Supports(tt.operation); got != tt.want {
+			t.Errorf("%s.Supports(%s) = %v, want %v", tt.pgCode, tt.operation, got, tt.want)
+		}
+	}
+}
+
+func TestCheckOperationNaverPayAutoPay(t *testing.T) {
+	err := checkOperation(Operation.TransactionAutoPay, PgCodeNaverPay)
+	if !errors.Is(err, ErrUnsupportedOperation) {
+		t.Fatalf("err = %v, want ErrUnsupportedOperation", err)
+	}
+	if !isLocalError(err) {
+		t.Fatal("지원하지 않는 기능 에러가 local error 가 아님")
+	}
+}
+
+func TestPgCodeUnmarshalText(t *testing.T) {
+	var code PgCode
+	if err := code.UnmarshalText([]byte(" NaverPay ")); err != nil || code != PgCodeNaverPay {
+		t.Fatalf("code = %q, err %v", code, err)
+	}
+}
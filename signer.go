@@ -0,0 +1,104 @@
+package payletter
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// HashParams recipe 에 따라 hash 에 포함되는 값
+type HashParams struct {
+	UserID  string
+	Amount  int
+	TID     string
+	BillKey string
+	Flag    string // password_skip_flag
+	ReqDate string
+}
+
+// HashRecipe 페이레터 API 별 hash 원문 구성, 원문은 parts + api key 를 이어 붙인 문자열
+type HashRecipe struct {
+	Name     string
+	UpperHex bool
+	parts    func(clientID string, p HashParams) []string
+}
+
+var (
+	// HashRecipePaymentCallback 결제 결과 payhash: user_id + amount + tid + api key
+	HashRecipePaymentCallback = HashRecipe{
+		Name:     "payment_callback",
+		UpperHex: true,
+		parts: func(_ string, p HashParams) []string {
+			return []string{p.UserID, strconv.Itoa(p.Amount), p.TID}
+		},
+	}
+	// HashRecipeEasyPayUser 간편결제 사용자 단위 요청 (등록, 목록 조회, 결제, 비밀번호, 탈퇴): client_id + user_id + req_date + api key
+	HashRecipeEasyPayUser = HashRecipe{
+		Name: "easy_pay_user",
+		parts: func(clientID string, p HashParams) []string {
+			return []string{clientID, p.UserID, p.ReqDate}
+		},
+	}
+	// HashRecipeEasyPayCancel 간편결제 취소: client_id + tid + amount + req_date + api key
+	HashRecipeEasyPayCancel = HashRecipe{
+		Name: "easy_pay_cancel",
+		parts: func(clientID string, p HashParams) []string {
+			return []string{clientID, p.TID, strconv.Itoa(p.Amount), p.ReqDate}
+		},
+	}
+	// HashRecipeEasyPayMethod 간편결제 결제 수단 삭제, 변경: client_id + user_id + billkey + req_date + api key
+	HashRecipeEasyPayMethod = HashRecipe{
+		Name: "easy_pay_method",
+		parts: func(clientID string, p HashParams) []string {
+			return []string{clientID, p.UserID, p.BillKey, p.ReqDate}
+		},
+	}
+	// HashRecipeEasyPayPasswordSkip 간편결제 비밀번호 생략 설정: client_id + user_id + password_skip_flag + req_date + api key
+	HashRecipeEasyPayPasswordSkip = HashRecipe{
+		Name: "easy_pay_password_skip",
+		parts: func(clientID string, p HashParams) []string {
+			return []string{clientID, p.UserID, p.Flag, p.ReqDate}
+		},
+	}
+)
+
+// Signer 요청 hash_data 생성, callback payhash 검증
+type Signer struct {
+	ClientID string
+	APIKey   string // payment api key
+}
+
+func NewSigner(clientID, apiKey string) Signer {
+	return Signer{
+		ClientID: clientID,
+		APIKey:   apiKey,
+	}
+}
+
+func (s Signer) Sign(recipe HashRecipe, p HashParams) string {
+	text := strings.Join(recipe.parts(s.ClientID, p), "") + s.APIKey
+	h := sha256.Sum256([]byte(text))
+
+	hash := hex.EncodeToString(h[:])
+	if recipe.UpperHex {
+		hash = strings.ToUpper(hash)
+	}
+	return hash
+}
+
+// Verify recipe 의 대소문자 그대로 상수 시간 비교
+func (s Signer) Verify(recipe HashRecipe, p HashParams, hash string) bool {
+	expected := s.Sign(recipe, p)
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(hash)) == 1
+}
+
+func userIDParam(userID int) string {
+	return strconv.Itoa(userID)
+}
+
+// Signer client id, payment api key 로 만든 Signer
+func (o ClientInfo) Signer() Signer {
+	return NewSigner(o.ClientID, o.PaymentAPIKey)
+}
@@ -0,0 +1,78 @@
+package payletter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSignerGoldenVectors(t *testing.T) {
+	signer := NewSigner("CID", "KEY")
+
+	tests := []struct {
+		recipe HashRecipe
+		params HashParams
+		want   string
+	}{
+		{
+			recipe: HashRecipePaymentCallback, // 1001000T001KEY
+			params: HashParams{UserID: "100", Amount: 1000, TID: "T001"},
+			want:   "69B56354F4EC6F6FC39E76C71C2099E796DCC629A6938E0575F3D4F2D4C11726",
+		},
+		{
+			recipe: HashRecipeEasyPayUser, // CIDuser120240301120000KEY
+			params: HashParams{UserID: "user1", ReqDate: "20240301120000"},
+			want:   "5e034f0502b95860f6fbe00ddf001af6adde236f6db9547a463761a9b51fb268",
+		},
+		{
+			recipe: HashRecipeEasyPayCancel, // CIDT001100020240301120000KEY
+			params: HashParams{TID: "T001", Amount: 1000, ReqDate: "20240301120000"},
+			want:   "d8d50f52fac939e8daa1ba3722db6ad73a72b4a388807b3517fed8456e2ed45a",
+		},
+		{
+			recipe: HashRecipeEasyPayMethod, // CIDuser1BK12320240301120000KEY
+			params: HashParams{UserID: "user1", BillKey: "BK123", ReqDate: "20240301120000"},
+			want:   "cc3aaf8ed5004a2616cc949347e7112faa074e6eaf6146f06c161ce5f9f69838",
+		},
+		{
+			recipe: HashRecipeEasyPayPasswordSkip, // CIDuser1Y20240301120000KEY
+			params: HashParams{UserID: "user1", Flag: "Y", ReqDate: "20240301120000"},
+			want:   "7550488d185c813dac2af1298bc28d81eb844185f418985e794c7a27af6fa1cc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.recipe.Name, func(t *testing.T) {
+			if got := signer.Sign(tt.recipe, tt.params); got != tt.want {
+				t.Fatalf("Sign = %s, want %s", got, tt.want)
+			}
+			if !signer.Verify(tt.recipe, tt.params, tt.want) {
+				t.Fatal("Verify 실패")
+			}
+		})
+	}
+}
+
+func TestSignerVerifyIsCaseSensitive(t *testing.T) {
+	signer := NewSigner("", "KEY")
+	params := HashParams{UserID: "100", Amount: 1000, TID: "T001"}
+	hash := signer.Sign(HashRecipePaymentCallback, params)
+
+	if signer.Verify(HashRecipePaymentCallback, params, strings.ToLower(hash)) {
+		t.Fatal("소문자 payhash 가 검증을 통과함")
+	}
+	if signer.Verify(HashRecipePaymentCallback, HashParams{UserID: "100", Amount: 1001, TID: "T001"}, hash) {
+		t.Fatal("다른 금액의 payhash 가 검증을 통과함")
+	}
+}
+
+func TestResPaymentDataValidate(t *testing.T) {
+	data := ResPaymentData{UserID: "100", Amount: 1000, Tid: "T001", PayHash: "69B56354F4EC6F6FC39E76C71C2099E796DCC629A6938E0575F3D4F2D4C11726"}
+	if err := data.Validate("KEY"); err != nil {
+		t.Fatal(err)
+	}
+
+	data.Amount = 10
+	if err := data.Validate("KEY"); err == nil {
+		t.Fatal("변조된 금액이 검증을 통과함")
+	}
+}
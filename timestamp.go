@@ -0,0 +1,113 @@
+package payletter
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidTime = errors.New("유효하지 않은 시각")
+
+var kst = time.FixedZone("KST", 9*60*60)
+
+// payletterTimeLayouts 페이레터 응답에 쓰이는 시각 형식
+var payletterTimeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"20060102150405",
+	"2006-01-02",
+	"20060102",
+}
+
+// Clock 현재 시각 제공, 테스트에서 고정 시각 주입용
+type Clock func() time.Time
+
+type Option func(o *options)
+
+type options struct {
+	clock Clock
+}
+
+// WithClock ReqDate 생성 등에 사용할 시계
+func WithClock(clock Clock) Option {
+	return func(o *options) {
+		o.clock = clock
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		clock: time.Now,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// now KST 현재 시각
+func (o options) now() time.Time {
+	return o.clock().In(kst)
+}
+
+// reqDate hash_data 에 쓰이는 요청 시각 (KST yyyyMMddHHmmss)
+func (o options) reqDate() string {
+	return o.now().Format(reqDateLayout)
+}
+
+// ParseTime 페이레터 시각 문자열을 KST 기준으로 변환
+func ParseTime(value string) (t time.Time, err error) {
+	for _, layout := range payletterTimeLayouts {
+		if len(value) != len(layout) {
+			continue
+		}
+		if t, err = time.ParseInLocation(layout, value, kst); err == nil {
+			return
+		}
+	}
+
+	err = fmt.Errorf("%w: %q", ErrInvalidTime, value)
+	return
+}
+
+// parseOptionalTime 값이 없으면 zero time
+func parseOptionalTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return ParseTime(value)
+}
+
+func (o *ResTransactionAutoPay) TransactionTime() (time.Time, error) {
+	return ParseTime(o.TransactionDate)
+}
+
+func (o *ResPaymentData) TransactionTime() (time.Time, error) {
+	return ParseTime(o.TransactionDate)
+}
+
+func (o *ResCancelEasyPay) CancelTime() (time.Time, error) {
+	return ParseTime(o.CancelDate)
+}
+
+func (o *ResPayLetterGetEasyPayMethods) JoinTime() (time.Time, error) {
+	return parseOptionalTime(o.JoinDate)
+}
+
+func (o *EasyPayMethod) MethodRegTime() (time.Time, error) {
+	return parseOptionalTime(o.MethodRegDate)
+}
+
+// LastTranTime 결제 이력이 없으면 zero time
+func (o *EasyPayMethod) LastTranTime() (time.Time, error) {
+	return parseOptionalTime(o.LastTranDate)
+}
+
+func (o *Transaction) TransactionTime() (time.Time, error) {
+	return ParseTime(o.TransactionDate)
+}
+
+// CancelTime 취소되지 않았으면 zero time
+func (o *Transaction) CancelTime() (time.Time, error) {
+	return parseOptionalTime(o.CancelDate)
+}
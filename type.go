@@ -1,11 +1,8 @@
 package payletter
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
 	"errors"
-	"fmt"
-	"strings"
+	"time"
 )
 
 type IPayLetter interface {
@@ -29,6 +26,34 @@ type IPayLetter interface {
 	TransactionNormalPay(req ReqTransactionNormalPay) (res ResTransactionNormalPay, err error)
 	// GetTransactionList 결제 내역 조회
 	GetTransactionList(req ReqGetTransactionList) (res ResGetTransactionList, err error)
+	// IssueVirtualAccount 가상계좌 발급
+	IssueVirtualAccount(req ReqIssueVirtualAccount) (res ResTransactionNormalPay, err error)
+	// RefundVirtualAccount 가상계좌 입금액 계좌 환불
+	RefundVirtualAccount(req ReqRefundVirtualAccount) (res ResCancelTransaction, err error)
+	// IssueCashReceipt 결제 건 현금영수증 발행
+	IssueCashReceipt(req ReqIssueCashReceipt) (res ResCashReceipt, err error)
+	// ReissueCashReceipt 발행된 현금영수증을 취소하고 새 정보로 재발행
+	ReissueCashReceipt(req ReqIssueCashReceipt) (res ResCashReceipt, err error)
+	// CancelCashReceipt 현금영수증 발행 취소
+	CancelCashReceipt(req ReqCancelCashReceipt) (res ResCashReceipt, err error)
+	// GetCashReceipt 현금영수증 발행 상태 조회
+	GetCashReceipt(req ReqGetCashReceipt) (res ResCashReceipt, err error)
+	// GetTransaction TID 또는 주문번호로 거래 한 건 조회, 단건 조회가 안 되면 해당 일자 거래 목록에서 찾음
+	GetTransaction(req ReqGetTransaction) (res ResGetTransaction, err error)
+	// DeleteEasyPayMethod 간편결제 등록한 결제 수단 삭제
+	DeleteEasyPayMethod(req ReqDeleteEasyPayMethod) (res ResUpdateEasyPayMethod, err error)
+	// RenameEasyPayMethod 간편결제 등록한 결제 수단 별칭 변경
+	RenameEasyPayMethod(req ReqRenameEasyPayMethod) (res ResUpdateEasyPayMethod, err error)
+	// SetFavoriteEasyPayMethod 간편결제 등록한 결제 수단을 즐겨찾기로 지정
+	SetFavoriteEasyPayMethod(req ReqSetFavoriteEasyPayMethod) (res ResUpdateEasyPayMethod, err error)
+	// UnregisterEasyPay 간편결제 회원 탈퇴, 등록한 모든 결제 수단 삭제
+	UnregisterEasyPay(req ReqUnregisterEasyPay) (res ResUpdateEasyPayMethod, err error)
+	// ResetEasyPayPassword 간편결제 비밀번호 재설정 화면 URL 발급
+	ResetEasyPayPassword(req ReqEasyPayPassword) (res ResEasyPayUI, err error)
+	// ChangeEasyPayPassword 간편결제 비밀번호 변경 화면 URL 발급
+	ChangeEasyPayPassword(req ReqEasyPayPassword) (res ResEasyPayUI, err error)
+	// SetEasyPayPasswordSkip 간편결제 비밀번호 입력 생략 설정 화면 URL 발급, 사용자가 비밀번호를 입력해야 변경 됨
+	SetEasyPayPasswordSkip(req ReqSetEasyPayPasswordSkip) (res ResEasyPayUI, err error)
 }
 
 type ClientInfo struct {
@@ -39,7 +64,7 @@ type ClientInfo struct {
 }
 
 type ReqRegisterAutoPay struct {
-	PgCode          string
+	PgCode          PgCode
 	ServiceName     string
 	UserID          int64
 	UserName        string
@@ -50,6 +75,7 @@ type ReqRegisterAutoPay struct {
 	ReturnUrl       string // POST 결제 성공 response = ResPaymentData
 	CancelUrl       string // GET 결제 중간에 취소
 	CallbackUrl     string // POST
+	AmountBreakdown
 }
 
 type ResRegisterAutoPay struct {
@@ -59,7 +85,7 @@ type ResRegisterAutoPay struct {
 }
 
 type ReqTransactionAutoPay struct {
-	PgCode      string `json:"pgcode"`
+	PgCode      PgCode `json:"pgcode"`
 	ServiceName string `json:"service_name"`
 	UserID      int64  `json:"user_id"`
 	UserName    string `json:"user_name"`
@@ -67,6 +93,7 @@ type ReqTransactionAutoPay struct {
 	Amount      int    `json:"amount"`
 	ProductName string `json:"product_name"`
 	BillKey     string `json:"billkey"`
+	AmountBreakdown
 }
 
 type reqTransactionAutoPay struct {
@@ -83,7 +110,7 @@ type ResTransactionAutoPay struct {
 }
 
 type CommonTransactionData struct {
-	PgCode          string
+	PgCode          PgCode
 	UserID          int
 	UserName        string
 	ServiceName     string
@@ -96,31 +123,36 @@ type CommonTransactionData struct {
 	ReturnUrl       string
 	CallbackUrl     string
 	CancelUrl       string
+	AmountBreakdown
 }
 
 type reqPaymentData struct {
-	PgCode          string `json:"pgcode"`
-	ClientID        string `json:"client_id"`
-	ServiceName     string `json:"service_name"`
-	UserID          int64  `json:"user_id"`
-	UserName        string `json:"user_name"`
-	OrderNo         string `json:"order_no"`
-	Amount          int    `json:"amount"`
-	ProductName     string `json:"product_name"`
-	EmailFlag       string `json:"email_flag"`
-	EmailAddr       string `json:"email_addr"`
-	AutoPayFlag     string `json:"autopay_flag"`
-	ReceiptFlag     string `json:"receipt_flag"`
-	CustomParameter string `json:"custom_parameter"`
-	ReturnUrl       string `json:"return_url"`
-	CallbackUrl     string `json:"callback_url"`
-	CancelUrl       string `json:"cancel_url"`
-	ReqDate         string `json:"req_date,omitempty"`
-	HashData        string `json:"hash_data,omitempty"`
-	BillKey         string `json:"billkey,omitempty"`
-	ReceiptType     string `json:"receipt_type,omitempty"`
-	ReceiptInfo     string `json:"receipt_info,omitempty"`
-	InstallMonth    string `json:"install_month,omitempty"`
+	PgCode          PgCode          `json:"pgcode"`
+	ClientID        string          `json:"client_id"`
+	ServiceName     string          `json:"service_name"`
+	UserID          int64           `json:"user_id"`
+	UserName        string          `json:"user_name"`
+	OrderNo         string          `json:"order_no"`
+	Amount          int             `json:"amount"`
+	ProductName     string          `json:"product_name"`
+	EmailFlag       string          `json:"email_flag"`
+	EmailAddr       string          `json:"email_addr"`
+	AutoPayFlag     string          `json:"autopay_flag"`
+	ReceiptFlag     string          `json:"receipt_flag"`
+	CustomParameter string          `json:"custom_parameter"`
+	ReturnUrl       string          `json:"return_url"`
+	CallbackUrl     string          `json:"callback_url"`
+	CancelUrl       string          `json:"cancel_url"`
+	ReqDate         string          `json:"req_date,omitempty"`
+	HashData        string          `json:"hash_data,omitempty"`
+	BillKey         string          `json:"billkey,omitempty"`
+	ReceiptType     CashReceiptType `json:"receipt_type,omitempty"`
+	ReceiptInfo     string          `json:"receipt_info,omitempty"`
+	InstallMonth    string          `json:"install_month,omitempty"`
+	BankCode        string          `json:"bank_code,omitempty"`
+	DepositorName   string          `json:"depositor_name,omitempty"`
+	ExpireDate      string          `json:"expire_date,omitempty"`
+	AmountBreakdown
 }
 
 type ResPaymentData struct {
@@ -139,7 +171,7 @@ type ResPaymentData struct {
 	CustomParameter      string `json:"custom_parameter" form:"custom_parameter"`
 	TransactionDate      string `json:"transaction_date" form:"transaction_date"`
 	PayInfo              string `json:"pay_info" form:"pay_info"`
-	PgCode               string `json:"pgcode" form:"pgcode"`
+	PgCode               PgCode `json:"pgcode" form:"pgcode"`
 	DomesticFlag         string `json:"domestic_flag" form:"domestic_flag"`
 	BillKey              string `json:"billkey" form:"billkey"`
 	InstallMonth         string `json:"install_month"`
@@ -159,19 +191,22 @@ type ResPaymentData struct {
 }
 
 func (o *ResPaymentData) Validate(paymentAPIKey string) (err error) {
-	pgHashText := fmt.Sprintf("%s%d%s%s", o.UserID, o.Amount, o.Tid, paymentAPIKey)
-	h := sha256.Sum256([]byte(pgHashText))
-	pgHash := strings.ToUpper(hex.EncodeToString(h[:]))
-
-	if pgHash != o.PayHash {
+	if !NewSigner("", paymentAPIKey).Verify(HashRecipePaymentCallback, HashParams{UserID: o.UserID, Amount: o.Amount, TID: o.Tid}, o.PayHash) {
 		err = errors.New("pgHash 검증 실패")
 	}
 
 	return
 }
 
+func (o *ResPaymentData) AmountBreakdown() AmountBreakdown {
+	return AmountBreakdown{
+		TaxAmount:     KRW(o.TaxAmount),
+		TaxFreeAmount: KRW(o.TaxFreeAmount),
+	}
+}
+
 func (o *ResPaymentData) ReplacePayInfo() {
-	if o.PgCode == PgCode.EasyBank {
+	if o.PgCode == PgCodeEasyBank {
 		o.PayInfo = BankCode[o.CardCode]
 	} else {
 		o.PayInfo = CardCode.ValueMap[o.CardCode]
@@ -179,7 +214,7 @@ func (o *ResPaymentData) ReplacePayInfo() {
 }
 
 type ReqCancelTransaction struct {
-	PgCode           string `json:"pgcode"`
+	PgCode           PgCode `json:"pgcode"`
 	UserID           int64  `json:"user_id"`
 	TID              string `json:"tid"`
 	NaverAPIClientId string `json:"-"`
@@ -198,12 +233,13 @@ type ResCancelTransaction struct {
 }
 
 type ReqPartialCancelTransaction struct {
-	PgCode           string `json:"pgcode"`
+	PgCode           PgCode `json:"pgcode"`
 	UserID           int64  `json:"user_id"`
 	TID              string `json:"tid"`
 	Amount           int    `json:"amount"`
 	NaverAPIClientId string `json:"-"`
 	NaverAPIKey      string `json:"-"`
+	AmountBreakdown
 }
 
 type reqPartialCancelTransaction struct {
@@ -221,7 +257,7 @@ type ReqRegisterEasyPay struct {
 	ClientID      string `json:"client_id"`
 	UserID        int    `json:"user_id"`
 	ServiceName   string `json:"service_name"`
-	PaymentMethod string `json:"payment_method"`
+	PaymentMethod PgCode `json:"payment_method"`
 	ReturnUrl     string `json:"return_url"`
 	CancelUrl     string `json:"cancel_url"`
 	ReqDate       string `json:"req_date"`
@@ -232,11 +268,14 @@ func (o *ReqRegisterEasyPay) setClientID(clientID string) {
 	o.ClientID = clientID
 }
 
-func (o *ReqRegisterEasyPay) setHashData(apiKey string, clientId string) {
-	originHashString := fmt.Sprintf("%s%d%s%s", clientId, o.UserID, o.ReqDate, apiKey)
-	h := sha256.Sum256([]byte(originHashString))
+func (o *ReqRegisterEasyPay) setReqDate(reqDate string) {
+	if o.ReqDate == "" {
+		o.ReqDate = reqDate
+	}
+}
 
-	o.HashData = hex.EncodeToString(h[:])
+func (o *ReqRegisterEasyPay) setHashData(signer Signer) {
+	o.HashData = signer.Sign(HashRecipeEasyPayUser, HashParams{UserID: userIDParam(o.UserID), ReqDate: o.ReqDate})
 }
 
 type ResEasyPayUI struct {
@@ -252,13 +291,14 @@ type ReqGetRegisteredEasyPayMethod struct {
 	ReqDate string `json:"req_date"`
 }
 
-func (o *ReqGetRegisteredEasyPayMethod) createHashData(apiKey string, clientId string) string {
-	originHashString := fmt.Sprintf("%s%d%s%s", clientId, o.UserID, o.ReqDate, apiKey)
-	h := sha256.Sum256([]byte(originHashString))
-
-	ownHash := hex.EncodeToString(h[:])
+func (o *ReqGetRegisteredEasyPayMethod) setReqDate(reqDate string) {
+	if o.ReqDate == "" {
+		o.ReqDate = reqDate
+	}
+}
 
-	return ownHash
+func (o *ReqGetRegisteredEasyPayMethod) createHashData(signer Signer) string {
+	return signer.Sign(HashRecipeEasyPayUser, HashParams{UserID: userIDParam(o.UserID), ReqDate: o.ReqDate})
 }
 
 type ResPayLetterGetEasyPayMethods struct {
@@ -271,14 +311,18 @@ type ResPayLetterGetEasyPayMethods struct {
 	Message          string               `json:"message,omitempty"`
 }
 
+func (o *ResPayLetterGetEasyPayMethods) IsPasswordSkipped() bool {
+	return o.PasswordSkipFlag == "Y"
+}
+
 type EasyPayMethodCount struct {
-	PaymentMethod string `json:"paymentMethod"`
+	PaymentMethod PgCode `json:"paymentMethod"`
 	Count         int    `json:"count"`
 }
 
 type EasyPayMethod struct {
 	// payletter response
-	PaymentMethod         string `json:"payment_method"`
+	PaymentMethod         PgCode `json:"payment_method"`
 	BillKey               string `json:"billkey"`
 	AliasName             string `json:"alias_name"`
 	FavoriteFlag          string `json:"favorite_flag"`
@@ -317,11 +361,14 @@ func (o *ReqCancelEasyPay) setIPAddress(ipAddr string) {
 	o.IpAddr = ipAddr
 }
 
-func (o *ReqCancelEasyPay) setHashData(clientId, apiKey string) {
-	originHashString := fmt.Sprintf("%s%s%d%s%s", clientId, o.Tid, o.Amount, o.ReqDate, apiKey)
-	h := sha256.Sum256([]byte(originHashString))
+func (o *ReqCancelEasyPay) setReqDate(reqDate string) {
+	if o.ReqDate == "" {
+		o.ReqDate = reqDate
+	}
+}
 
-	o.HashData = hex.EncodeToString(h[:])
+func (o *ReqCancelEasyPay) setHashData(signer Signer) {
+	o.HashData = signer.Sign(HashRecipeEasyPayCancel, HashParams{TID: o.Tid, Amount: o.Amount, ReqDate: o.ReqDate})
 }
 
 type ResCancelEasyPay struct {
@@ -333,21 +380,174 @@ type ResCancelEasyPay struct {
 	Message    string `json:"message"`
 }
 
+// easyPayMethodHashData 등록한 결제 수단 하나를 변경하는 요청의 hash_data
+func easyPayMethodHashData(signer Signer, userID int, billKey, reqDate string) string {
+	return signer.Sign(HashRecipeEasyPayMethod, HashParams{UserID: userIDParam(userID), BillKey: billKey, ReqDate: reqDate})
+}
+
+type ReqDeleteEasyPayMethod struct {
+	ClientID string `json:"client_id"`
+	UserID   int    `json:"user_id"`
+	BillKey  string `json:"billkey"`
+	ReqDate  string `json:"req_date"`
+	HashData string `json:"hash_data"`
+}
+
+func (o *ReqDeleteEasyPayMethod) setClientID(clientID string) {
+	o.ClientID = clientID
+}
+
+func (o *ReqDeleteEasyPayMethod) setReqDate(reqDate string) {
+	if o.ReqDate == "" {
+		o.ReqDate = reqDate
+	}
+}
+
+func (o *ReqDeleteEasyPayMethod) setHashData(signer Signer) {
+	o.HashData = easyPayMethodHashData(signer, o.UserID, o.BillKey, o.ReqDate)
+}
+
+type ReqRenameEasyPayMethod struct {
+	ClientID  string `json:"client_id"`
+	UserID    int    `json:"user_id"`
+	BillKey   string `json:"billkey"`
+	AliasName string `json:"alias_name"`
+	ReqDate   string `json:"req_date"`
+	HashData  string `json:"hash_data"`
+}
+
+func (o *ReqRenameEasyPayMethod) setClientID(clientID string) {
+	o.ClientID = clientID
+}
+
+func (o *ReqRenameEasyPayMethod) setReqDate(reqDate string) {
+	if o.ReqDate == "" {
+		o.ReqDate = reqDate
+	}
+}
+
+func (o *ReqRenameEasyPayMethod) setHashData(signer Signer) {
+	o.HashData = easyPayMethodHashData(signer, o.UserID, o.BillKey, o.ReqDate)
+}
+
+type ReqSetFavoriteEasyPayMethod struct {
+	ClientID     string `json:"client_id"`
+	UserID       int    `json:"user_id"`
+	BillKey      string `json:"billkey"`
+	FavoriteFlag string `json:"favorite_flag"` // Y: 즐겨찾기 지정, N: 해제
+	ReqDate      string `json:"req_date"`
+	HashData     string `json:"hash_data"`
+}
+
+func (o *ReqSetFavoriteEasyPayMethod) setClientID(clientID string) {
+	o.ClientID = clientID
+}
+
+func (o *ReqSetFavoriteEasyPayMethod) setReqDate(reqDate string) {
+	if o.ReqDate == "" {
+		o.ReqDate = reqDate
+	}
+}
+
+func (o *ReqSetFavoriteEasyPayMethod) setHashData(signer Signer) {
+	o.HashData = easyPayMethodHashData(signer, o.UserID, o.BillKey, o.ReqDate)
+}
+
+type ReqUnregisterEasyPay struct {
+	ClientID string `json:"client_id"`
+	UserID   int    `json:"user_id"`
+	ReqDate  string `json:"req_date"`
+	HashData string `json:"hash_data"`
+}
+
+func (o *ReqUnregisterEasyPay) setClientID(clientID string) {
+	o.ClientID = clientID
+}
+
+func (o *ReqUnregisterEasyPay) setReqDate(reqDate string) {
+	if o.ReqDate == "" {
+		o.ReqDate = reqDate
+	}
+}
+
+func (o *ReqUnregisterEasyPay) setHashData(signer Signer) {
+	o.HashData = signer.Sign(HashRecipeEasyPayUser, HashParams{UserID: userIDParam(o.UserID), ReqDate: o.ReqDate})
+}
+
+type ReqEasyPayPassword struct {
+	ClientID    string `json:"client_id"`
+	UserID      int    `json:"user_id"`
+	ServiceName string `json:"service_name"`
+	ReturnUrl   string `json:"return_url"`
+	CancelUrl   string `json:"cancel_url"`
+	ReqDate     string `json:"req_date"`
+	HashData    string `json:"hash_data"`
+}
+
+func (o *ReqEasyPayPassword) setClientID(clientID string) {
+	o.ClientID = clientID
+}
+
+func (o *ReqEasyPayPassword) setReqDate(reqDate string) {
+	if o.ReqDate == "" {
+		o.ReqDate = reqDate
+	}
+}
+
+func (o *ReqEasyPayPassword) setHashData(signer Signer) {
+	o.HashData = signer.Sign(HashRecipeEasyPayUser, HashParams{UserID: userIDParam(o.UserID), ReqDate: o.ReqDate})
+}
+
+type ReqSetEasyPayPasswordSkip struct {
+	ClientID         string `json:"client_id"`
+	UserID           int    `json:"user_id"`
+	ServiceName      string `json:"service_name"`
+	PasswordSkipFlag string `json:"password_skip_flag"` // Y: 비밀번호 입력 생략, N: 매 결제마다 입력
+	ReturnUrl        string `json:"return_url"`
+	CancelUrl        string `json:"cancel_url"`
+	ReqDate          string `json:"req_date"`
+	HashData         string `json:"hash_data"`
+}
+
+func (o *ReqSetEasyPayPasswordSkip) setClientID(clientID string) {
+	o.ClientID = clientID
+}
+
+func (o *ReqSetEasyPayPasswordSkip) setReqDate(reqDate string) {
+	if o.ReqDate == "" {
+		o.ReqDate = reqDate
+	}
+}
+
+func (o *ReqSetEasyPayPasswordSkip) setHashData(signer Signer) {
+	o.HashData = signer.Sign(HashRecipeEasyPayPasswordSkip, HashParams{UserID: userIDParam(o.UserID), Flag: o.PasswordSkipFlag, ReqDate: o.ReqDate})
+}
+
+type ResUpdateEasyPayMethod struct {
+	UserID  int    `json:"user_id"`
+	BillKey string `json:"billkey,omitempty"` // 회원 탈퇴는 빈 값
+	Code    *int   `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
 type ReqTransactionEasyPay struct {
 	CommonTransactionData
 	ReqDate      string
 	BillKey      string
 	ReceiptFlag  string
-	ReceiptType  string
-	ReceiptInfo  string
+	ReceiptType  CashReceiptType
+	ReceiptInfo  string // 소득공제: 휴대폰 번호 또는 현금영수증 카드 번호, 지출증빙: 사업자 번호
 	InstallMonth int
 }
 
-func (o *ReqTransactionEasyPay) createHashData(clientId, apiKey string) string {
-	originHashString := fmt.Sprintf("%s%d%s%s", clientId, o.UserID, o.ReqDate, apiKey)
-	h := sha256.Sum256([]byte(originHashString))
+func (o *ReqTransactionEasyPay) setReqDate(reqDate string) {
+	if o.ReqDate == "" {
+		o.ReqDate = reqDate
+	}
+}
 
-	return hex.EncodeToString(h[:])
+func (o *ReqTransactionEasyPay) createHashData(signer Signer) string {
+	return signer.Sign(HashRecipeEasyPayUser, HashParams{UserID: userIDParam(o.UserID), ReqDate: o.ReqDate})
 }
 
 type ReqTransactionNormalPay struct {
@@ -368,7 +568,7 @@ type ResTransactionNormalPay struct {
 type ReqGetTransactionList struct {
 	Date              string `json:"date"`
 	DateType          string `json:"date_type"`
-	PgCode            string `json:"pgcode"`
+	PgCode            PgCode `json:"pgcode"`
 	NaverAPIClientID  string `json:"naver_api_client_id"`
 	NaverAPISearchKey string `json:"naver_api_search_key"`
 }
@@ -376,7 +576,7 @@ type ReqGetTransactionList struct {
 type reqGetTransactionList struct {
 	Date     string `json:"date"`
 	DateType string `json:"date_type"`
-	PgCode   string `json:"pgcode"`
+	PgCode   PgCode `json:"pgcode"`
 	ClientID string `json:"client_id"`
 }
 
@@ -388,7 +588,7 @@ type ResGetTransactionList struct {
 }
 
 type Transaction struct {
-	PgCode          string `json:"pgcode"`
+	PgCode          PgCode `json:"pgcode"`
 	UserID          string `json:"user_id"`
 	UserName        string `json:"user_name"`
 	TID             string `json:"tid"`
@@ -402,3 +602,228 @@ type Transaction struct {
 	TransactionDate string `json:"transaction_date"`
 	CancelDate      string `json:"cancel_date"`
 }
+
+func (o *Transaction) AmountBreakdown() AmountBreakdown {
+	return AmountBreakdown{
+		TaxAmount:     KRW(o.TaxAmount),
+		TaxFreeAmount: KRW(o.TaxFreeAmount),
+	}
+}
+
+type ReqIssueVirtualAccount struct {
+	CommonTransactionData
+	BankCode      string    // 입금 은행 코드 (BankCode), 비어 있으면 결제 창에서 선택
+	DepositorName string    // 입금자명
+	ExpireAt      time.Time // 입금 기한, zero 이면 페이레터 기본값
+}
+
+// ResVirtualAccountData 가상계좌 발급, 입금 완료 callback
+type ResVirtualAccountData struct {
+	ResPaymentData
+	AccountNo     string `json:"account_no" form:"account_no"`
+	AccountHolder string `json:"account_holder" form:"account_holder"` // 예금주
+	BankCode      string `json:"bank_code" form:"bank_code"`
+	DepositorName string `json:"depositor_name" form:"depositor_name"`
+	ExpireDate    string `json:"expire_date" form:"expire_date"`
+	DepositDate   string `json:"deposit_date" form:"deposit_date"` // 입금 전이면 빈 값
+}
+
+func (o *ResVirtualAccountData) BankName() string {
+	return BankCode[o.BankCode]
+}
+
+func (o *ResVirtualAccountData) IsDeposited() bool {
+	return o.DepositDate != ""
+}
+
+func (o *ResVirtualAccountData) ExpireTime() (time.Time, error) {
+	return parseOptionalTime(o.ExpireDate)
+}
+
+// DepositTime 입금 전이면 zero time
+func (o *ResVirtualAccountData) DepositTime() (time.Time, error) {
+	return parseOptionalTime(o.DepositDate)
+}
+
+// IsExpired 입금 기한이 지나도록 입금되지 않음
+func (o *ResVirtualAccountData) IsExpired(now time.Time) bool {
+	if o.IsDeposited() {
+		return false
+	}
+	expireAt, err := o.ExpireTime()
+	if err != nil || expireAt.IsZero() {
+		return false
+	}
+	return now.After(expireAt)
+}
+
+// ReqRefundVirtualAccount 가상계좌 입금액을 환불 계좌로 전체 환불
+type ReqRefundVirtualAccount struct {
+	PgCode              PgCode `json:"pgcode"`
+	UserID              int64  `json:"user_id"`
+	TID                 string `json:"tid"`
+	RefundBankCode      string `json:"refund_bank_code"`
+	RefundAccountNo     string `json:"refund_account_no"`
+	RefundAccountHolder string `json:"refund_account_holder"`
+}
+
+type reqRefundVirtualAccount struct {
+	ClientInfo
+	ReqRefundVirtualAccount
+}
+
+// CashReceiptType 현금영수증 용도
+type CashReceiptType string
+
+const (
+	CashReceiptTypeDeduction CashReceiptType = "deduction" // 소득공제
+	CashReceiptTypeEvidence  CashReceiptType = "evidence"  // 지출증빙
+)
+
+type ReqIssueCashReceipt struct {
+	PgCode      PgCode          `json:"pgcode"`
+	UserID      int64           `json:"user_id"`
+	TID         string          `json:"tid"`
+	ReceiptType CashReceiptType `json:"receipt_type"`
+	ReceiptInfo string          `json:"receipt_info"`
+	Amount      int             `json:"amount,omitempty"` // 0 이면 결제 금액 전체
+}
+
+type reqIssueCashReceipt struct {
+	ClientInfo
+	ReqIssueCashReceipt
+}
+
+type ReqCancelCashReceipt struct {
+	PgCode PgCode `json:"pgcode"`
+	UserID int64  `json:"user_id"`
+	TID    string `json:"tid"`
+}
+
+type reqCancelCashReceipt struct {
+	ClientInfo
+	ReqCancelCashReceipt
+}
+
+type ReqGetCashReceipt struct {
+	PgCode PgCode `json:"pgcode"`
+	TID    string `json:"tid"`
+}
+
+type ResCashReceipt struct {
+	TID         string          `json:"tid"`
+	CID         string          `json:"cid"`
+	DealNo      string          `json:"deal_no"` // 현금영수증 승인 번호
+	ReceiptType CashReceiptType `json:"receipt_type"`
+	Amount      int             `json:"amount"`
+	Status      string          `json:"status"` // issued, cancelled
+	IssueDate   string          `json:"issue_date"`
+	CancelDate  string          `json:"cancel_date"`
+	Code        *int            `json:"code,omitempty"`
+	Message     string          `json:"message,omitempty"`
+}
+
+func (o *ResCashReceipt) IsCancelled() bool {
+	return o.CancelDate != ""
+}
+
+func (o *ResCashReceipt) IssueTime() (time.Time, error) {
+	return parseOptionalTime(o.IssueDate)
+}
+
+// CancelTime 취소되지 않았으면 zero time
+func (o *ResCashReceipt) CancelTime() (time.Time, error) {
+	return parseOptionalTime(o.CancelDate)
+}
+
+var ErrTransactionNotFound = errors.New("거래 없음")
+
+type ReqGetTransaction struct {
+	PgCode            PgCode `json:"pgcode"`
+	TID               string `json:"tid"`      // TID 또는 OrderNo 중 하나 필수
+	OrderNo           string `json:"order_no"` // TID 가 있으면 무시
+	Date              string `json:"date"`     // 목록 조회로 찾을 때 사용할 거래 일자 (yyyyMMdd), 비어 있으면 오늘 (KST)
+	NaverAPIClientID  string `json:"naver_api_client_id"`
+	NaverAPISearchKey string `json:"naver_api_search_key"`
+}
+
+type TransactionCancel struct {
+	TID        string `json:"tid"`
+	Amount     int    `json:"amount"`
+	CancelDate string `json:"cancel_date"`
+}
+
+func (o *TransactionCancel) CancelTime() (time.Time, error) {
+	return ParseTime(o.CancelDate)
+}
+
+type ResGetTransaction struct {
+	Code    *int    `json:"code,omitempty"`
+	Message *string `json:"message,omitempty"`
+	Transaction
+	CancelledAmount int                 `json:"cancelled_amount"` // 누적 취소 금액
+	CancelHistory   []TransactionCancel `json:"cancel_history"`
+	CardCode        string              `json:"card_code"` // 신용카드 결제가 아니면 빈 값
+	CardNo          string              `json:"card_no"`   // 마스킹 된 카드 번호
+	InstallMonth    int                 `json:"install_month"`
+	ApprovalNo      string              `json:"approval_no"`
+	// Incomplete 목록에서 원 거래를 찾지 못하고 취소 건만 찾음, Amount 는 0 이고 남은 금액, 전체 취소 여부를 알 수 없음
+	Incomplete bool `json:"incomplete"`
+}
+
+func (o *ResGetTransaction) CardName() string {
+	return CardCode.ValueMap[o.CardCode]
+}
+
+func (o *ResGetTransaction) RemainingAmount() int {
+	return o.Amount - o.CancelledAmount
+}
+
+func (o *ResGetTransaction) IsCancelled() bool {
+	return o.Amount > 0 && o.RemainingAmount() <= 0
+}
+
+// newResGetTransaction 거래 목록에서 찾은 거래, 목록에는 카드 정보가 없음
+//
+// 취소 건은 목록에 원 거래와 같은 주문번호의 별도 행으로 내려옴
+func newResGetTransaction(rows []Transaction) (res ResGetTransaction) {
+	for _, row := range rows {
+		if row.CancelDate == "" {
+			res.Transaction = row
+			continue
+		}
+		res.CancelHistory = append(res.CancelHistory, TransactionCancel{
+			TID:        row.TID,
+			Amount:     row.Amount,
+			CancelDate: row.CancelDate,
+		})
+		res.CancelledAmount += row.Amount
+	}
+	if res.TID == "" && len(rows) > 0 {
+		// 원 거래가 다른 일자라 목록에 없으면 취소 건 정보로 채우고, 원 거래 금액은 알 수 없음
+		res.Transaction = rows[0]
+		res.Amount = 0
+		res.CancelDate = ""
+		res.Incomplete = true
+	}
+	return
+}
+
+// findTransactions TID 또는 주문번호가 같은 거래 (원 거래, 취소 건)
+func findTransactions(list []Transaction, tid, orderNo string) (rows []Transaction) {
+	for _, t := range list {
+		if (tid != "" && t.TID == tid) || (tid == "" && t.OrderNo == orderNo) {
+			rows = append(rows, t)
+		}
+	}
+	if tid != "" && len(rows) > 0 { // 취소 건은 TID 가 다를 수 있어 주문번호로 한번 더 찾음
+		orderNo = rows[0].OrderNo
+		rows = rows[:0]
+		for _, t := range list {
+			if t.TID == tid || (orderNo != "" && t.OrderNo == orderNo) {
+				rows = append(rows, t)
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,487 @@
+package payletter
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	reqDateLayout         = "20060102150405"
+	transactionDateLayout = "20060102"
+	maxInstallMonth       = 36 // 카드사 최대 할부 개월 수, 결제 수단별 한도는 InstallmentPolicy
+)
+
+var ErrValidation = errors.New("요청 검증 실패")
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationError 요청의 모든 필드 검증 실패 목록
+type ValidationError struct {
+	Fields []FieldError `json:"fields"`
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		messages = append(messages, f.Error())
+	}
+	return fmt.Sprintf("%s: %s", ErrValidation.Error(), strings.Join(messages, ", "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrValidation
+}
+
+// isLocalError 페이레터를 호출하기 전에 요청을 거부한 에러
+func isLocalError(err error) bool {
+	return errors.Is(err, ErrValidation) || errors.Is(err, ErrUnsupportedOperation)
+}
+
+// validate 실패한 규칙(nil 이 아닌 FieldError)을 모아 ValidationError 로 반환
+func validate(groups ...[]*FieldError) error {
+	fields := make([]FieldError, 0)
+	for _, group := range groups {
+		for _, f := range group {
+			if f != nil {
+				fields = append(fields, *f)
+			}
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: fields}
+}
+
+func rules(r ...*FieldError) []*FieldError {
+	return r
+}
+
+func fieldError(field, format string, args ...any) *FieldError {
+	return &FieldError{Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+func required(field, value string) *FieldError {
+	if strings.TrimSpace(value) == "" {
+		return fieldError(field, "필수 값")
+	}
+	return nil
+}
+
+func positiveID[T int | int64](field string, id T) *FieldError {
+	if id <= 0 {
+		return fieldError(field, "0 보다 커야 함")
+	}
+	return nil
+}
+
+func amountRange(field string, amount, min int) *FieldError {
+	if amount < min {
+		return fieldError(field, "%d 이상이어야 함", min)
+	}
+	return nil
+}
+
+func oneOf(field, value string, allowed ...string) *FieldError {
+	for _, a := range allowed {
+		if value == a {
+			return nil
+		}
+	}
+	return fieldError(field, "허용되지 않은 값 %q (%s)", value, strings.Join(allowed, ", "))
+}
+
+func validPgCode(field string, code PgCode) *FieldError {
+	if code == "" {
+		return fieldError(field, "필수 값")
+	}
+	if !code.IsValid() {
+		return fieldError(field, "알 수 없는 결제 수단 %q", code)
+	}
+	return nil
+}
+
+func validOrderNo(field, orderNo string) *FieldError {
+	if err := ValidateOrderNo(orderNo); err != nil {
+		return fieldError(field, "%s", strings.TrimPrefix(err.Error(), ErrInvalidOrderNo.Error()+": "))
+	}
+	return nil
+}
+
+func dateFormat(field, value, layout string) *FieldError {
+	if value == "" {
+		return fieldError(field, "필수 값")
+	}
+	if _, err := time.Parse(layout, value); err != nil || len(value) != len(layout) {
+		return fieldError(field, "%s 형식이어야 함", layout)
+	}
+	return nil
+}
+
+func urlFormat(field, value string, isRequired bool) *FieldError {
+	if value == "" {
+		if isRequired {
+			return fieldError(field, "필수 값")
+		}
+		return nil
+	}
+
+	u, err := url.ParseRequestURI(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fieldError(field, "유효하지 않은 URL")
+	}
+	return nil
+}
+
+func flag(field, value string) *FieldError {
+	if value == "" {
+		return nil
+	}
+	return oneOf(field, value, "Y", "N")
+}
+
+func installMonth(field string, month int) *FieldError {
+	if month < 0 || month == 1 || month > maxInstallMonth {
+		return fieldError(field, "0(일시불) 또는 2 ~ %d 이어야 함", maxInstallMonth)
+	}
+	return nil
+}
+
+func naverCredentials(pgCode PgCode, clientID, apiKey string) []*FieldError {
+	if !pgCode.NeedsSeparateCredentials() {
+		return nil
+	}
+	return rules(
+		required("naver_api_client_id", clientID),
+		required("naver_api_key", apiKey),
+	)
+}
+
+func (o CommonTransactionData) rules() []*FieldError {
+	r := rules(
+		validPgCode("pgcode", o.PgCode),
+		positiveID("user_id", o.UserID),
+		validOrderNo("order_no", o.OrderNo),
+		amountRange("amount", o.Amount, 1),
+		required("product_name", o.ProductName),
+		flag("email_flag", o.EmailFlag),
+		urlFormat("return_url", o.ReturnUrl, true),
+		urlFormat("callback_url", o.CallbackUrl, false),
+		urlFormat("cancel_url", o.CancelUrl, false),
+	)
+	if o.EmailFlag == "Y" {
+		r = append(r, required("email_addr", o.EmailAddr))
+	}
+	return append(r, o.AmountBreakdown.rules(o.Amount)...)
+}
+
+func (o ReqRegisterAutoPay) Validate() error {
+	return validate(rules(
+		validPgCode("pgcode", o.PgCode),
+		positiveID("user_id", o.UserID),
+		validOrderNo("order_no", o.OrderNo),
+		amountRange("amount", o.Amount, 0), // 0원 인증 등록 허용
+		required("product_name", o.ProductName),
+		urlFormat("return_url", o.ReturnUrl, true),
+		urlFormat("callback_url", o.CallbackUrl, false),
+		urlFormat("cancel_url", o.CancelUrl, false),
+	), o.AmountBreakdown.rules(o.Amount))
+}
+
+func (o ReqTransactionAutoPay) Validate() error {
+	return validate(rules(
+		validPgCode("pgcode", o.PgCode),
+		positiveID("user_id", o.UserID),
+		required("order_no", o.OrderNo), // idempotency, 배치 결제 재개의 key, 형식은 가맹점마다 달라 검사하지 않음
+		amountRange("amount", o.Amount, 1),
+		required("product_name", o.ProductName),
+		required("billkey", o.BillKey),
+	), o.AmountBreakdown.rules(o.Amount))
+}
+
+func (o ReqCancelTransaction) Validate() error {
+	return validate(rules(
+		validPgCode("pgcode", o.PgCode),
+		positiveID("user_id", o.UserID),
+		required("tid", o.TID),
+	), naverCredentials(o.PgCode, o.NaverAPIClientId, o.NaverAPIKey))
+}
+
+func (o ReqPartialCancelTransaction) Validate() error {
+	return validate(rules(
+		validPgCode("pgcode", o.PgCode),
+		positiveID("user_id", o.UserID),
+		required("tid", o.TID),
+		amountRange("amount", o.Amount, 1),
+	), o.AmountBreakdown.rules(o.Amount), naverCredentials(o.PgCode, o.NaverAPIClientId, o.NaverAPIKey))
+}
+
+func (o ReqRegisterEasyPay) Validate() error {
+	return validate(rules(
+		positiveID("user_id", o.UserID),
+		oneOf("payment_method", o.PaymentMethod.String(), PgCodeCreditCard.String(), PgCodeEasyBank.String()),
+		urlFormat("return_url", o.ReturnUrl, true),
+		urlFormat("cancel_url", o.CancelUrl, false),
+		dateFormat("req_date", o.ReqDate, reqDateLayout),
+	))
+}
+
+func (o ReqEasyPayPassword) Validate() error {
+	return validate(rules(
+		positiveID("user_id", o.UserID),
+		urlFormat("return_url", o.ReturnUrl, true),
+		urlFormat("cancel_url", o.CancelUrl, false),
+		dateFormat("req_date", o.ReqDate, reqDateLayout),
+	))
+}
+
+func (o ReqSetEasyPayPasswordSkip) Validate() error {
+	return validate(rules(
+		positiveID("user_id", o.UserID),
+		oneOf("password_skip_flag", o.PasswordSkipFlag, "Y", "N"),
+		urlFormat("return_url", o.ReturnUrl, true),
+		urlFormat("cancel_url", o.CancelUrl, false),
+		dateFormat("req_date", o.ReqDate, reqDateLayout),
+	))
+}
+
+func (o ReqGetRegisteredEasyPayMethod) Validate() error {
+	return validate(rules(
+		positiveID("user_id", o.UserID),
+		dateFormat("req_date", o.ReqDate, reqDateLayout),
+	))
+}
+
+func (o ReqCancelEasyPay) Validate() error {
+	return validate(rules(
+		positiveID("user_id", o.UserID),
+		required("tid", o.Tid),
+		amountRange("amount", o.Amount, 1),
+		dateFormat("req_date", o.ReqDate, reqDateLayout),
+	))
+}
+
+func (o ReqDeleteEasyPayMethod) Validate() error {
+	return validate(rules(
+		positiveID("user_id", o.UserID),
+		required("billkey", o.BillKey),
+		dateFormat("req_date", o.ReqDate, reqDateLayout),
+	))
+}
+
+func (o ReqRenameEasyPayMethod) Validate() error {
+	return validate(rules(
+		positiveID("user_id", o.UserID),
+		required("billkey", o.BillKey),
+		required("alias_name", o.AliasName),
+		dateFormat("req_date", o.ReqDate, reqDateLayout),
+	))
+}
+
+func (o ReqSetFavoriteEasyPayMethod) Validate() error {
+	return validate(rules(
+		positiveID("user_id", o.UserID),
+		required("billkey", o.BillKey),
+		oneOf("favorite_flag", o.FavoriteFlag, "Y", "N"),
+		dateFormat("req_date", o.ReqDate, reqDateLayout),
+	))
+}
+
+func (o ReqUnregisterEasyPay) Validate() error {
+	return validate(rules(
+		positiveID("user_id", o.UserID),
+		dateFormat("req_date", o.ReqDate, reqDateLayout),
+	))
+}
+
+func (o ReqTransactionEasyPay) Validate() error {
+	return validate(o.CommonTransactionData.rules(), rules(
+		dateFormat("req_date", o.ReqDate, reqDateLayout),
+		flag("receipt_flag", o.ReceiptFlag),
+		installMonth("install_month", o.InstallMonth),
+	), cashReceiptInfo(o.ReceiptFlag == "Y", o.ReceiptType, o.ReceiptInfo))
+}
+
+func (o ReqTransactionNormalPay) Validate() error {
+	return validate(
+		o.CommonTransactionData.rules(),
+		naverCredentials(o.PgCode, o.NaverAPIClientId, o.NaverAPIKey),
+	)
+}
+
+func bankCode(field, code string, isRequired bool) *FieldError {
+	if code == "" {
+		if isRequired {
+			return fieldError(field, "필수 값")
+		}
+		return nil
+	}
+	if _, exists := BankCode[code]; !exists {
+		return fieldError(field, "알 수 없는 은행 코드 %q", code)
+	}
+	return nil
+}
+
+func accountNo(field, value string) *FieldError {
+	if value == "" {
+		return fieldError(field, "필수 값")
+	}
+	if len(value) < 6 || len(value) > 20 || strings.Trim(value, "0123456789") != "" {
+		return fieldError(field, "6 ~ 20 자리 숫자여야 함")
+	}
+	return nil
+}
+
+func (o ReqIssueVirtualAccount) Validate() error {
+	return validate(o.CommonTransactionData.rules(), rules(
+		bankCode("bank_code", o.BankCode, false),
+	))
+}
+
+func (o ReqRefundVirtualAccount) Validate() error {
+	return validate(rules(
+		validPgCode("pgcode", o.PgCode),
+		positiveID("user_id", o.UserID),
+		required("tid", o.TID),
+		bankCode("refund_bank_code", o.RefundBankCode, true),
+		accountNo("refund_account_no", o.RefundAccountNo),
+		required("refund_account_holder", o.RefundAccountHolder),
+	))
+}
+
+func digitsOnly(value string) string {
+	return strings.NewReplacer("-", "", " ", "").Replace(value)
+}
+
+func isDigits(value string) bool {
+	return value != "" && strings.Trim(value, "0123456789") == ""
+}
+
+// ValidateMobileNo 휴대폰 번호 (01x, 10 ~ 11 자리), - 는 무시
+func ValidateMobileNo(mobileNo string) error {
+	n := digitsOnly(mobileNo)
+	if !isDigits(n) || !strings.HasPrefix(n, "01") || len(n) < 10 || len(n) > 11 {
+		return errors.New("유효하지 않은 휴대폰 번호")
+	}
+	return nil
+}
+
+// ValidateBusinessNo 사업자 등록 번호 (10 자리, 검증 번호 포함), - 는 무시
+func ValidateBusinessNo(businessNo string) error {
+	n := digitsOnly(businessNo)
+	if !isDigits(n) || len(n) != 10 {
+		return errors.New("유효하지 않은 사업자 번호")
+	}
+
+	weights := []int{1, 3, 7, 1, 3, 7, 1, 3, 5}
+	sum := 0
+	for i, w := range weights {
+		sum += int(n[i]-'0') * w
+	}
+	sum += int(n[8]-'0') * 5 / 10
+
+	if (10-sum%10)%10 != int(n[9]-'0') {
+		return errors.New("유효하지 않은 사업자 번호")
+	}
+	return nil
+}
+
+// ValidateCashReceiptInfo 소득공제는 휴대폰 번호 또는 현금영수증 카드 번호(13 ~ 19 자리), 지출증빙은 사업자 번호
+func ValidateCashReceiptInfo(receiptType CashReceiptType, receiptInfo string) error {
+	switch receiptType {
+	case CashReceiptTypeDeduction:
+		n := digitsOnly(receiptInfo)
+		if isDigits(n) && len(n) >= 13 && len(n) <= 19 {
+			return nil
+		}
+		return ValidateMobileNo(receiptInfo)
+	case CashReceiptTypeEvidence:
+		return ValidateBusinessNo(receiptInfo)
+	default:
+		return fmt.Errorf("알 수 없는 현금영수증 용도 %q", receiptType)
+	}
+}
+
+func cashReceiptInfo(isRequired bool, receiptType CashReceiptType, receiptInfo string) []*FieldError {
+	if !isRequired {
+		return nil
+	}
+	if err := oneOf("receipt_type", string(receiptType), string(CashReceiptTypeDeduction), string(CashReceiptTypeEvidence)); err != nil {
+		return rules(err)
+	}
+	if err := ValidateCashReceiptInfo(receiptType, receiptInfo); err != nil {
+		return rules(fieldError("receipt_info", "%s", err.Error()))
+	}
+	return nil
+}
+
+func (o ReqIssueCashReceipt) Validate() error {
+	return validate(rules(
+		validPgCode("pgcode", o.PgCode),
+		positiveID("user_id", o.UserID),
+		required("tid", o.TID),
+		amountRange("amount", o.Amount, 0),
+	), cashReceiptInfo(true, o.ReceiptType, o.ReceiptInfo))
+}
+
+func (o ReqCancelCashReceipt) Validate() error {
+	return validate(rules(
+		validPgCode("pgcode", o.PgCode),
+		positiveID("user_id", o.UserID),
+		required("tid", o.TID),
+	))
+}
+
+func (o ReqGetCashReceipt) Validate() error {
+	return validate(rules(
+		validPgCode("pgcode", o.PgCode),
+		required("tid", o.TID),
+	))
+}
+
+func (o ReqGetTransaction) Validate() error {
+	r := rules(
+		validPgCode("pgcode", o.PgCode),
+	)
+	if o.TID == "" && o.OrderNo == "" {
+		r = append(r, fieldError("tid", "tid 또는 order_no 필수"))
+	}
+	if o.Date != "" {
+		r = append(r, dateFormat("date", o.Date, transactionDateLayout))
+	}
+	if o.PgCode.NeedsSeparateCredentials() {
+		r = append(r,
+			required("naver_api_client_id", o.NaverAPIClientID),
+			required("naver_api_search_key", o.NaverAPISearchKey),
+		)
+	}
+	return validate(r)
+}
+
+func (o ReqGetTransactionList) Validate() error {
+	r := rules(
+		dateFormat("date", o.Date, transactionDateLayout),
+		oneOf("date_type", o.DateType, TransactionDateType.Transaction, TransactionDateType.Settle),
+	)
+	if o.PgCode != "" { // 비어 있으면 전체 결제 수단
+		r = append(r, validPgCode("pgcode", o.PgCode))
+	}
+	if o.PgCode.NeedsSeparateCredentials() {
+		r = append(r,
+			required("naver_api_client_id", o.NaverAPIClientID),
+			required("naver_api_search_key", o.NaverAPISearchKey),
+		)
+	}
+	return validate(r)
+}
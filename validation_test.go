@@ -0,0 +1,48 @@
+package payletter
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestReqTransactionAutoPayAcceptsExistingOrderNo(t *testing.T) {
+	req := ReqTransactionAutoPay{
+		PgCode:      PgCodeCreditCard,
+		UserID:      1,
+		OrderNo:     "legacy order #1/2024", // 기존 시스템의 주문번호 형식
+		Amount:      1000,
+		ProductName: "정기 결제",
+		BillKey:     "billkey",
+	}
+	if err := req.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	req.BillKey = ""
+	var validationErr *ValidationError
+	if err := req.Validate(); !errors.As(err, &validationErr) || validationErr.Fields[0].Field != "billkey" {
+		t.Fatalf("err = %v", err)
+	}
+
+	req.BillKey, req.OrderNo = "billkey", ""
+	if err := req.Validate(); !errors.As(err, &validationErr) || validationErr.Fields[0].Field != "order_no" {
+		t.Fatalf("주문번호 없음: err = %v", err)
+	}
+}
+
+func TestReqGetTransactionListPgCodeIsOptional(t *testing.T) {
+	req := ReqGetTransactionList{Date: "20240301", DateType: TransactionDateType.Transaction}
+	if err := req.Validate(); err != nil {
+		t.Fatalf("pgcode 없이 전체 조회: %v", err)
+	}
+
+	req.PgCode = "unknown"
+	if err := req.Validate(); !errors.Is(err, ErrValidation) {
+		t.Fatalf("err = %v, want ErrValidation", err)
+	}
+
+	req.PgCode = PgCodeNaverPay
+	if err := req.Validate(); !errors.Is(err, ErrValidation) {
+		t.Fatalf("네이버페이 인증 정보 없이 조회: %v", err)
+	}
+}